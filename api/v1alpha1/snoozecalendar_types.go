@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultICalendarKey is the ConfigMap key read when an ICalendarSource does
+// not name one.
+const DefaultICalendarKey = "calendar.ics"
+
+// CalendarDate is a single date listed by a SnoozeCalendar.
+type CalendarDate struct {
+	// Date is a YYYY-MM-DD date.
+	// +kubebuilder:validation:Pattern=`^\d{4}-\d{2}-\d{2}$`
+	Date string `json:"date"`
+	// Name describes the date, such as the holiday it is.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ICalendarSource imports the events of an iCalendar (.ics) file held in a
+// ConfigMap. Every date an event covers is listed. Recurrence rules are not
+// expanded, so a yearly holiday needs an event for each year.
+type ICalendarSource struct {
+	// ConfigMapName is the name of a ConfigMap in the calendar's namespace.
+	ConfigMapName string `json:"configMapName"`
+	// Key is the ConfigMap key holding the file. Defaults to calendar.ics.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// SnoozeCalendarSpec defines the dates of a SnoozeCalendar.
+type SnoozeCalendarSpec struct {
+	// Dates are the dates listed inline.
+	// +optional
+	Dates []CalendarDate `json:"dates,omitempty"`
+
+	// ICalendar adds the dates of an iCalendar file.
+	// +optional
+	ICalendar *ICalendarSource `json:"iCalendar,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnoozeCalendar is the Schema for the snoozecalendars API. It lists holidays
+// and one-off dates that SnoozeWindows in the same namespace reference.
+type SnoozeCalendar struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnoozeCalendarSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnoozeCalendarList contains a list of SnoozeCalendar.
+type SnoozeCalendarList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnoozeCalendar `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnoozeCalendar{}, &SnoozeCalendarList{})
+}
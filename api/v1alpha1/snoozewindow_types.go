@@ -17,27 +17,548 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Resource types a SnoozeWindow can manage.
+const (
+	ResourceTypeDeployment  = "deployment"
+	ResourceTypeStatefulSet = "statefulset"
+	ResourceTypeJob         = "job"
+	ResourceTypeCronJob     = "cronjob"
+	// ResourceTypeService is only managed by windows that set spec.activator.
+	ResourceTypeService = "service"
+)
+
+// OptInLabel is the label selected by a SnoozeWindow that does not set its own
+// labelSelector.
+const OptInLabel = "kube-snooze/enabled"
+
+// WakeUntilAnnotation holds the resources of a SnoozeWindow, or a single
+// workload it selects, awake until the given RFC 3339 time even while the
+// window is active. Snoozing resumes once the time has passed.
+const WakeUntilAnnotation = "kube-snooze/wake-until"
+
+// SnoozeUntilAnnotation snoozes the resources of a SnoozeWindow right away,
+// outside of its schedule, until the given RFC 3339 time. It is equivalent to
+// spec.suspendUntil.
+const SnoozeUntilAnnotation = "kube-snooze/snooze-until"
+
+// SnoozedByAnnotation records the SnoozeWindow that snoozed a resource. A
+// resource selected by several windows is woken when none of them keeps it
+// asleep any longer.
+const SnoozedByAnnotation = "kube-snooze/snoozed-by"
+
+// SnoozingAtAnnotation is set on the resources of a window during its warning
+// period to the time they are snoozed at, in RFC 3339.
+const SnoozingAtAnnotation = "kube-snooze/snoozing-at"
+
+// HookLabel names the lifecycle hook that created a Job. SnoozeWindows never
+// snooze hook Jobs.
+const HookLabel = "kube-snooze/hook"
+
+// WindowLabel names the SnoozeWindow that created a hook Job.
+const WindowLabel = "kube-snooze/window"
+
+// TierAnnotation places a resource in a tier of its SnoozeWindow, counted from
+// 0 in the order of spec.tiers, in place of the tier its labels select.
+const TierAnnotation = "kube-snooze/tier"
+
+// DependsOnAnnotation lists, comma separated, the KIND/NAME resources of the
+// same window a resource needs running. It is woken in a tier after theirs
+// and snoozed before them.
+const DependsOnAnnotation = "kube-snooze/depends-on"
+
+// Condition types reported in SnoozeWindowStatus.
+const (
+	// ConditionWakeOverride is true while a wake override keeps the window or
+	// some of its resources awake.
+	ConditionWakeOverride = "WakeOverride"
+	// ConditionManualSnooze is true while the window's resources are snoozed on
+	// demand through spec.suspendUntil or the snooze-until annotation.
+	ConditionManualSnooze = "ManualSnooze"
+	// ConditionSuspended is true while spec.suspended stops the controller from
+	// acting on the window.
+	ConditionSuspended = "Suspended"
+	// ConditionDryRun is true while spec.dryRun keeps the controller from
+	// changing the window's resources.
+	ConditionDryRun = "DryRun"
+	// ConditionConflict is true while another SnoozeWindow selects some of the
+	// window's resources.
+	ConditionConflict = "Conflict"
+	// ConditionSequencing is true while a snooze or wake waits for a tier of
+	// resources to scale down or become ready before moving to the next one.
+	ConditionSequencing = "Sequencing"
+	// ConditionWakeVerified is true once every workload woken by the last wake
+	// is available again, unknown while they are checked on and false when
+	// they were not available within spec.wakeTimeout.
+	ConditionWakeVerified = "WakeVerified"
+	// ConditionWakeDegraded is true when workloads woken by the last wake were
+	// not available within spec.wakeTimeout, or have pods crash-looping.
+	ConditionWakeDegraded = "WakeDegraded"
+	// ConditionLifecycleHooks reports the hooks of the last transition: unknown
+	// while they run, true once they succeeded and false when one failed.
+	ConditionLifecycleHooks = "LifecycleHooks"
+	// ConditionSnoozeWarning is true during the warning period before a
+	// scheduled snooze.
+	ConditionSnoozeWarning = "SnoozeWarning"
+	// ConditionIdleSnooze reports the activity of the workloads of a window
+	// with spec.idleSnooze: true while they are snoozed for being idle, false
+	// while they are awake and unknown when their activity cannot be measured.
+	ConditionIdleSnooze = "IdleSnooze"
+)
+
+// Actions reported for scheduled transitions and dry-run windows.
+const (
+	ActionSnooze = "Snooze"
+	ActionWake   = "Wake"
+)
+
+// SuspendPolicy decides what happens to the resources of a SnoozeWindow when
+// it is suspended.
+// +kubebuilder:validation:Enum=Wake;Leave
+type SuspendPolicy string
+
+const (
+	// SuspendPolicyWake wakes the resources the window snoozed.
+	SuspendPolicyWake SuspendPolicy = "Wake"
+	// SuspendPolicyLeave leaves the resources as they are.
+	SuspendPolicyLeave SuspendPolicy = "Leave"
+)
+
+// IdleSource is where the activity of a window's workloads is measured.
+// +kubebuilder:validation:Enum=CPU;Prometheus
+type IdleSource string
+
+const (
+	// IdleSourceCPU sums the CPU usage of the workloads' pods, as reported by
+	// the Kubernetes metrics API.
+	IdleSourceCPU IdleSource = "CPU"
+	// IdleSourcePrometheus runs a PromQL query against the operator's
+	// --prometheus-url.
+	IdleSourcePrometheus IdleSource = "Prometheus"
+)
+
+// HookFailurePolicy decides what a failed lifecycle hook does to the
+// transition it runs for.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail holds a snooze back, leaving the resources awake
+	// until the next transition, and reports a wake as degraded.
+	HookFailurePolicyFail HookFailurePolicy = "Fail"
+	// HookFailurePolicyIgnore carries on with the transition.
+	HookFailurePolicyIgnore HookFailurePolicy = "Ignore"
+)
+
+// LifecycleHook runs a Job at a transition of a SnoozeWindow. The hooks of a
+// transition run one after the other.
+type LifecycleHook struct {
+	// Name identifies the hook in the names of its Jobs, in status and Events.
+	Name string `json:"name"`
+	// JobTemplateRef names a CronJob in the window's namespace whose job
+	// template the hook runs. The CronJob is usually suspended, to only serve
+	// as a template, and the window never snoozes or wakes it.
+	JobTemplateRef corev1.LocalObjectReference `json:"jobTemplateRef"`
+	// Timeout is how long the Job may run before it fails. Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy is Fail to gate the transition on the hook's success or
+	// Ignore to carry on when it fails. Defaults to Fail.
+	// +kubebuilder:default=Fail
+	// +optional
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// CalendarMode decides how a SnoozeWindow uses the dates of a SnoozeCalendar.
+// +kubebuilder:validation:Enum=Holiday;Skip
+type CalendarMode string
+
+const (
+	// CalendarModeHoliday snoozes all day on the calendar's dates, in addition
+	// to the schedule.
+	CalendarModeHoliday CalendarMode = "Holiday"
+	// CalendarModeSkip does not snooze on the calendar's dates. Runs of the
+	// schedule that start on one of them are skipped.
+	CalendarModeSkip CalendarMode = "Skip"
+)
+
+// CalendarReference references a SnoozeCalendar in the window's namespace.
+type CalendarReference struct {
+	// Name is the name of the SnoozeCalendar.
+	Name string `json:"name"`
+	// Mode is Holiday to snooze all day on the calendar's dates or Skip to not
+	// snooze on them. Defaults to Holiday.
+	// +kubebuilder:default=Holiday
+	// +optional
+	Mode CalendarMode `json:"mode,omitempty"`
+}
+
 // SnoozeWindowSpec defines the desired state of SnoozeWindow.
 type SnoozeWindowSpec struct {
-	LabelSelector  map[string]string  `json:"labelSelector,omitempty"`
-	Timezone       string             `json:"timezone"`
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+	// Timezone defaults to the operator-wide default timezone when empty.
+	// +optional
+	Timezone       string             `json:"timezone,omitempty"`
 	SnoozeSchedule SnoozeScheduleSpec `json:"snoozeSchedule,omitempty"`
+
+	// SnoozeSchedules lists several schedules in place of SnoozeSchedule. The
+	// window is active whenever any of them is, so "weeknights" and "all
+	// weekend" can share one window.
+	// +optional
+	SnoozeSchedules []SnoozeScheduleSpec `json:"snoozeSchedules,omitempty"`
+
+	// Calendars adjusts the schedule with the dates of SnoozeCalendars, to
+	// snooze all day on holidays or to skip snoozing on dates such as release
+	// weekends. A date that is both skipped and a holiday is skipped.
+	// +optional
+	Calendars []CalendarReference `json:"calendars,omitempty"`
+
+	// ResourceTypes limits the window to the listed resource types
+	// (deployment, statefulset, job, cronjob, and service with an activator).
+	// All types are managed when empty.
+	// +optional
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+
+	// SuspendUntil snoozes the selected resources right away, regardless of the
+	// schedule, and wakes them once the time has passed.
+	// +optional
+	SuspendUntil *metav1.Time `json:"suspendUntil,omitempty"`
+
+	// Suspended stops the controller from acting on the window without deleting it.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// SuspendPolicy decides whether suspending the window wakes the resources
+	// it snoozed or leaves them as they are. Defaults to Wake.
+	// +kubebuilder:default=Wake
+	// +optional
+	SuspendPolicy SuspendPolicy `json:"suspendPolicy,omitempty"`
+
+	// Priority decides between windows that select the same resource. The
+	// highest priority window that is snoozing it or holding it awake with a
+	// wake override decides; on a tie, any window snoozing it keeps it asleep.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Tiers orders the window's resources. On wake each tier waits for the
+	// resources of the previous one to be ready before it starts; on snooze
+	// the order is reversed and each tier waits for the one before to scale
+	// down. A resource belongs to the first tier that selects it, and
+	// resources no tier selects come last on wake.
+	// +optional
+	Tiers []SnoozeTier `json:"tiers,omitempty"`
+
+	// WakeTimeout is how long the workloads woken by the window have to become
+	// available again before the wake is reported as degraded. Defaults to 10m.
+	// +optional
+	WakeTimeout *metav1.Duration `json:"wakeTimeout,omitempty"`
+
+	// WakeRate paces the window's wakes, on top of the operator-wide limits.
+	// +optional
+	WakeRate *WakeRateSpec `json:"wakeRate,omitempty"`
+
+	// WakeLeadTime starts waking the window's resources this long before the
+	// end of each run of its schedule, so they are ready by the time it ends.
+	// +optional
+	WakeLeadTime *metav1.Duration `json:"wakeLeadTime,omitempty"`
+
+	// AutoTuneWakeLeadTime replaces WakeLeadTime, for each workload, with the
+	// time it took to become available on its previous wakes, plus a quarter as
+	// margin. Workloads without a wake on record use WakeLeadTime.
+	// +optional
+	AutoTuneWakeLeadTime bool `json:"autoTuneWakeLeadTime,omitempty"`
+
+	// PreSnoozeHooks run before the window snoozes its resources, which are
+	// snoozed once the hooks have succeeded.
+	// +optional
+	PreSnoozeHooks []LifecycleHook `json:"preSnoozeHooks,omitempty"`
+
+	// PostWakeHooks run once the resources woken by the window are available.
+	// +optional
+	PostWakeHooks []LifecycleHook `json:"postWakeHooks,omitempty"`
+
+	// Notifications posts a message to a webhook when the window snoozes or
+	// wakes its resources, or fails to.
+	// +optional
+	Notifications *NotificationSpec `json:"notifications,omitempty"`
+
+	// WarningPeriod is how long before each scheduled snooze the window warns
+	// about it with an Event, a notification and the snoozing-at annotation on
+	// the resources it is about to snooze, leaving time to set a wake override.
+	// +optional
+	WarningPeriod *metav1.Duration `json:"warningPeriod,omitempty"`
+
+	// Activator points the window's Services at the activator while they are
+	// snoozed, which wakes their workloads on the first request.
+	// +optional
+	Activator *ActivatorSpec `json:"activator,omitempty"`
+
+	// IdleSnooze snoozes the window's workloads once their activity stayed
+	// below a threshold for a while. A window with IdleSnooze needs no
+	// schedule. An idle snooze has no end and is never requeued: it ends with
+	// a wake override on the window, and without spec.activator nothing else
+	// wakes the workloads.
+	// +optional
+	IdleSnooze *IdleSnoozeSpec `json:"idleSnooze,omitempty"`
+
+	// DryRun evaluates the schedule and selector and reports the resources the
+	// window would snooze or wake, validating each change with a server-side
+	// dry run instead of applying it.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// SnoozeTier selects the resources of a window that are snoozed and woken
+// together. An empty tier selects every resource.
+type SnoozeTier struct {
+	// Name identifies the tier in status conditions and Events.
+	Name string `json:"name"`
+	// LabelSelector selects the resources of the tier among those of the window.
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+	// ResourceTypes limits the tier to the listed resource types.
+	// +optional
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+}
+
+// ActivatorSpec routes the requests to the snoozed Services of a window through
+// the activator, which holds each request until the workloads behind the
+// Service are woken and available.
+type ActivatorSpec struct {
+	// Service is the namespace/name of the activator's Service. Defaults to
+	// the operator's --activator-service.
+	// +optional
+	Service string `json:"service,omitempty"`
+	// IdleTimeout is how long workloads woken by a request stay awake after
+	// the last request. Defaults to 15m.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+}
+
+// IdleSnoozeSpec snoozes the workloads of a window after a period of low
+// activity. The controller never requeues the window to end an idle snooze,
+// the workloads stay snoozed until a wake override on the window. With
+// spec.activator a request to one of their Services also wakes them, for the
+// activator's idle timeout.
+type IdleSnoozeSpec struct {
+	// Source is where activity is measured. Defaults to CPU.
+	// +kubebuilder:default=CPU
+	// +optional
+	Source IdleSource `json:"source,omitempty"`
+	// Query is the PromQL query measuring the activity of the workloads, such
+	// as their request rate, for the Prometheus source. It is a Go template
+	// given .Namespace and .Window. The values of the result are summed, and
+	// an empty result counts as no activity.
+	// +optional
+	Query string `json:"query,omitempty"`
+	// Threshold is the activity below which the workloads are idle: the CPU
+	// cores used by all of their pods together, such as "50m", or the value
+	// of Query.
+	Threshold resource.Quantity `json:"threshold"`
+	// Lookback is how long the activity has to stay below Threshold before
+	// the workloads are snoozed. Defaults to 30m.
+	// +optional
+	Lookback *metav1.Duration `json:"lookback,omitempty"`
+	// MinAwake is how long the workloads stay awake after the window last
+	// woke them, or was created, however idle they are. Defaults to 1h.
+	// +optional
+	MinAwake *metav1.Duration `json:"minAwake,omitempty"`
+}
+
+// WakeRateSpec spreads the wakes of a window so that windows ending at the same
+// time do not start every workload at once.
+type WakeRateSpec struct {
+	// ResourcesPerMinute wakes at most this many of the window's resources a
+	// minute, evenly spaced.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ResourcesPerMinute int32 `json:"resourcesPerMinute,omitempty"`
+	// Jitter delays each scheduled wake by up to this long, by an offset that
+	// is fixed for the window. It replaces the operator's --wake-jitter.
+	// +optional
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+}
+
+// NotificationEvent is a transition of a SnoozeWindow that is notified about.
+// +kubebuilder:validation:Enum=Snoozing;Snoozed;Woken;Failed
+type NotificationEvent string
+
+const (
+	// NotificationEventSnoozing is sent when the warning period before a
+	// scheduled snooze starts.
+	NotificationEventSnoozing NotificationEvent = "Snoozing"
+	// NotificationEventSnoozed is sent once the window snoozed its resources.
+	NotificationEventSnoozed NotificationEvent = "Snoozed"
+	// NotificationEventWoken is sent once the window woke its resources.
+	NotificationEventWoken NotificationEvent = "Woken"
+	// NotificationEventFailed is sent when snoozing or waking a resource failed.
+	NotificationEventFailed NotificationEvent = "Failed"
+)
+
+// NotificationSpec posts the transitions of a window to an HTTP webhook, as a
+// JSON body with a text field that Slack incoming webhooks accept.
+type NotificationSpec struct {
+	// URL is the webhook messages are posted to. Its host has to be one the
+	// operator allows with --notification-hosts.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// SecretRef names a Secret in the window's namespace. Its url key takes
+	// the place of URL, which keeps Slack webhook URLs out of the spec, and
+	// its token key is sent as a bearer token.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// Events lists the transitions that are notified about. Defaults to all
+	// of them.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+	// Template is a Go template for the text of a message. It is given the
+	// .Window, .Namespace, .Event, .Resources and .Error of the transition.
+	// +optional
+	Template string `json:"template,omitempty"`
+	// Retries is how many times a message the webhook did not accept is sent
+	// again, backing off exponentially. Defaults to 3.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Retries *int32 `json:"retries,omitempty"`
 }
 
+// SnoozeScheduleSpec defines when a SnoozeWindow snoozes its resources, in the
+// window's timezone. A schedule either runs from StartTime to EndTime once on
+// Date or weekly on Days, or starts at the instants matched by Cron and lasts
+// for Duration.
 type SnoozeScheduleSpec struct {
-	StartTime string   `json:"startTime"`
-	EndTime   string   `json:"endTime"`
-	Days      []string `json:"days,omitempty"`      // Not Implemented
+	// StartTime and EndTime are HH:MM times of day. A window whose end time is
+	// before its start time ends on the following day.
+	StartTime string   `json:"startTime,omitempty"`
+	EndTime   string   `json:"endTime,omitempty"`
+	Days      []string `json:"days,omitempty"`
 	Frequency string   `json:"frequency,omitempty"` // Not Implemented
-	Date      string   `json:"date,omitempty"`      // Not Implemented
+	Date      string   `json:"date,omitempty"`
+
+	// Cron is a five field cron expression, such as "0 19 * * MON-FRI", for the
+	// instants the window starts. It replaces StartTime, EndTime, Days and Date.
+	// +optional
+	Cron string `json:"cron,omitempty"`
+	// Duration is how long a window started by Cron lasts.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Exclusions lists YYYY-MM-DD dates on which the window does not start.
+	// +optional
+	Exclusions []string `json:"exclusions,omitempty"`
 }
 
 type SnoozeWindowStatus struct {
 	SleepyInstances int                `json:"sleepy_instances,omitempty"`
 	Conditions      []metav1.Condition `json:"conditions,omitempty"`
+
+	// WakeOverrideUntil is the time the window's wake override ends, while one
+	// is in effect.
+	// +optional
+	WakeOverrideUntil *metav1.Time `json:"wakeOverrideUntil,omitempty"`
+
+	// ManualSnoozeUntil is the time an on-demand snooze ends, while one is in
+	// effect.
+	// +optional
+	ManualSnoozeUntil *metav1.Time `json:"manualSnoozeUntil,omitempty"`
+
+	// IdleSince is when the activity of the window's workloads last fell
+	// below spec.idleSnooze.threshold, while it stays there.
+	// +optional
+	IdleSince *metav1.Time `json:"idleSince,omitempty"`
+
+	// IdleSnoozeTime is when the window snoozed its workloads for being idle,
+	// while they stay snoozed.
+	// +optional
+	IdleSnoozeTime *metav1.Time `json:"idleSnoozeTime,omitempty"`
+
+	// LastWakeTime is when the window last started waking its resources. The
+	// woken workloads are checked on until they are available or
+	// spec.wakeTimeout has passed.
+	// +optional
+	LastWakeTime *metav1.Time `json:"lastWakeTime,omitempty"`
+
+	// WakeDurations records how long the window's workloads took to become
+	// available after being woken, to tune their wake lead time.
+	// +optional
+	WakeDurations []WakeDuration `json:"wakeDurations,omitempty"`
+
+	// DryRunChanges lists the changes a dry-run window would make to its
+	// resources at the last reconcile.
+	// +optional
+	DryRunChanges []DryRunChange `json:"dryRunChanges,omitempty"`
+
+	// NextTransitions previews the next times the window snoozes and wakes its
+	// resources.
+	// +optional
+	NextTransitions []ScheduledTransition `json:"nextTransitions,omitempty"`
+
+	// Savings estimates the resources the window saved by keeping replicas snoozed.
+	// +optional
+	Savings *SavingsStatus `json:"savings,omitempty"`
+}
+
+// ScheduledTransition is an upcoming snooze or wake of a SnoozeWindow.
+type ScheduledTransition struct {
+	// Action is Snooze or Wake.
+	Action string `json:"action"`
+	// Time is the instant of the transition.
+	Time metav1.Time `json:"time"`
+	// LocalTime is Time in the window's timezone, in RFC 3339 format.
+	LocalTime string `json:"localTime"`
+}
+
+// DryRunChange is a snooze or wake a dry-run window would make.
+type DryRunChange struct {
+	// Action is Snooze or Wake.
+	Action string `json:"action"`
+	// Kind is the resource type, such as deployment.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// WakeDuration records the wakes of a single workload.
+type WakeDuration struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// WokenAt is when the workload was last woken.
+	// +optional
+	WokenAt *metav1.Time `json:"wokenAt,omitempty"`
+	// AvailableAt is when the workload was last found available after a wake.
+	// +optional
+	AvailableAt *metav1.Time `json:"availableAt,omitempty"`
+	// Duration averages the time the workload took to become available over
+	// its recorded wakes, weighing the latest as much as all the ones before.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// SavingsStatus estimates the CPU and memory the snoozed replicas of a window
+// would have requested, from their pod template requests and the replica count
+// recorded when they were snoozed. Totals are decimal strings.
+type SavingsStatus struct {
+	// SnoozedCPU is the CPU the currently snoozed replicas would request.
+	SnoozedCPU resource.Quantity `json:"snoozedCPU,omitempty"`
+	// SnoozedMemory is the memory the currently snoozed replicas would request.
+	SnoozedMemory resource.Quantity `json:"snoozedMemory,omitempty"`
+
+	// CPUCoreHours is the total CPU saved, in core hours.
+	CPUCoreHours string `json:"cpuCoreHours,omitempty"`
+	// MemoryGiBHours is the total memory saved, in GiB hours.
+	MemoryGiBHours string `json:"memoryGiBHours,omitempty"`
+	// EstimatedCost prices the totals with the operator's pricing ConfigMap.
+	// +optional
+	EstimatedCost string `json:"estimatedCost,omitempty"`
+
+	// LastUpdateTime is the time the totals were last brought up to date.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,10 +21,288 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivatorSpec) DeepCopyInto(out *ActivatorSpec) {
+	*out = *in
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivatorSpec.
+func (in *ActivatorSpec) DeepCopy() *ActivatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalendarDate) DeepCopyInto(out *CalendarDate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalendarDate.
+func (in *CalendarDate) DeepCopy() *CalendarDate {
+	if in == nil {
+		return nil
+	}
+	out := new(CalendarDate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalendarReference) DeepCopyInto(out *CalendarReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalendarReference.
+func (in *CalendarReference) DeepCopy() *CalendarReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CalendarReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunChange) DeepCopyInto(out *DryRunChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunChange.
+func (in *DryRunChange) DeepCopy() *DryRunChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ICalendarSource) DeepCopyInto(out *ICalendarSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ICalendarSource.
+func (in *ICalendarSource) DeepCopy() *ICalendarSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ICalendarSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleSnoozeSpec) DeepCopyInto(out *IdleSnoozeSpec) {
+	*out = *in
+	out.Threshold = in.Threshold.DeepCopy()
+	if in.Lookback != nil {
+		in, out := &in.Lookback, &out.Lookback
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinAwake != nil {
+		in, out := &in.MinAwake, &out.MinAwake
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleSnoozeSpec.
+func (in *IdleSnoozeSpec) DeepCopy() *IdleSnoozeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleSnoozeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHook) DeepCopyInto(out *LifecycleHook) {
+	*out = *in
+	out.JobTemplateRef = in.JobTemplateRef
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHook.
+func (in *LifecycleHook) DeepCopy() *LifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSpec) DeepCopyInto(out *NotificationSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSpec.
+func (in *NotificationSpec) DeepCopy() *NotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavingsStatus) DeepCopyInto(out *SavingsStatus) {
+	*out = *in
+	out.SnoozedCPU = in.SnoozedCPU.DeepCopy()
+	out.SnoozedMemory = in.SnoozedMemory.DeepCopy()
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavingsStatus.
+func (in *SavingsStatus) DeepCopy() *SavingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SavingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTransition) DeepCopyInto(out *ScheduledTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTransition.
+func (in *ScheduledTransition) DeepCopy() *ScheduledTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnoozeCalendar) DeepCopyInto(out *SnoozeCalendar) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeCalendar.
+func (in *SnoozeCalendar) DeepCopy() *SnoozeCalendar {
+	if in == nil {
+		return nil
+	}
+	out := new(SnoozeCalendar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnoozeCalendar) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnoozeCalendarList) DeepCopyInto(out *SnoozeCalendarList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SnoozeCalendar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeCalendarList.
+func (in *SnoozeCalendarList) DeepCopy() *SnoozeCalendarList {
+	if in == nil {
+		return nil
+	}
+	out := new(SnoozeCalendarList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnoozeCalendarList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnoozeCalendarSpec) DeepCopyInto(out *SnoozeCalendarSpec) {
+	*out = *in
+	if in.Dates != nil {
+		in, out := &in.Dates, &out.Dates
+		*out = make([]CalendarDate, len(*in))
+		copy(*out, *in)
+	}
+	if in.ICalendar != nil {
+		in, out := &in.ICalendar, &out.ICalendar
+		*out = new(ICalendarSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeCalendarSpec.
+func (in *SnoozeCalendarSpec) DeepCopy() *SnoozeCalendarSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnoozeCalendarSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnoozeScheduleSpec) DeepCopyInto(out *SnoozeScheduleSpec) {
 	*out = *in
@@ -33,6 +311,16 @@ func (in *SnoozeScheduleSpec) DeepCopyInto(out *SnoozeScheduleSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeScheduleSpec.
@@ -45,6 +333,33 @@ func (in *SnoozeScheduleSpec) DeepCopy() *SnoozeScheduleSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnoozeTier) DeepCopyInto(out *SnoozeTier) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceTypes != nil {
+		in, out := &in.ResourceTypes, &out.ResourceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeTier.
+func (in *SnoozeTier) DeepCopy() *SnoozeTier {
+	if in == nil {
+		return nil
+	}
+	out := new(SnoozeTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnoozeWindow) DeepCopyInto(out *SnoozeWindow) {
 	*out = *in
@@ -115,6 +430,83 @@ func (in *SnoozeWindowSpec) DeepCopyInto(out *SnoozeWindowSpec) {
 		}
 	}
 	in.SnoozeSchedule.DeepCopyInto(&out.SnoozeSchedule)
+	if in.SnoozeSchedules != nil {
+		in, out := &in.SnoozeSchedules, &out.SnoozeSchedules
+		*out = make([]SnoozeScheduleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Calendars != nil {
+		in, out := &in.Calendars, &out.Calendars
+		*out = make([]CalendarReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceTypes != nil {
+		in, out := &in.ResourceTypes, &out.ResourceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuspendUntil != nil {
+		in, out := &in.SuspendUntil, &out.SuspendUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]SnoozeTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WakeTimeout != nil {
+		in, out := &in.WakeTimeout, &out.WakeTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WakeRate != nil {
+		in, out := &in.WakeRate, &out.WakeRate
+		*out = new(WakeRateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WakeLeadTime != nil {
+		in, out := &in.WakeLeadTime, &out.WakeLeadTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PreSnoozeHooks != nil {
+		in, out := &in.PreSnoozeHooks, &out.PreSnoozeHooks
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostWakeHooks != nil {
+		in, out := &in.PostWakeHooks, &out.PostWakeHooks
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarningPeriod != nil {
+		in, out := &in.WarningPeriod, &out.WarningPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Activator != nil {
+		in, out := &in.Activator, &out.Activator
+		*out = new(ActivatorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleSnooze != nil {
+		in, out := &in.IdleSnooze, &out.IdleSnooze
+		*out = new(IdleSnoozeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeWindowSpec.
@@ -137,6 +529,50 @@ func (in *SnoozeWindowStatus) DeepCopyInto(out *SnoozeWindowStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WakeOverrideUntil != nil {
+		in, out := &in.WakeOverrideUntil, &out.WakeOverrideUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.ManualSnoozeUntil != nil {
+		in, out := &in.ManualSnoozeUntil, &out.ManualSnoozeUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.IdleSince != nil {
+		in, out := &in.IdleSince, &out.IdleSince
+		*out = (*in).DeepCopy()
+	}
+	if in.IdleSnoozeTime != nil {
+		in, out := &in.IdleSnoozeTime, &out.IdleSnoozeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastWakeTime != nil {
+		in, out := &in.LastWakeTime, &out.LastWakeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.WakeDurations != nil {
+		in, out := &in.WakeDurations, &out.WakeDurations
+		*out = make([]WakeDuration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DryRunChanges != nil {
+		in, out := &in.DryRunChanges, &out.DryRunChanges
+		*out = make([]DryRunChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextTransitions != nil {
+		in, out := &in.NextTransitions, &out.NextTransitions
+		*out = make([]ScheduledTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Savings != nil {
+		in, out := &in.Savings, &out.Savings
+		*out = new(SavingsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozeWindowStatus.
@@ -148,3 +584,51 @@ func (in *SnoozeWindowStatus) DeepCopy() *SnoozeWindowStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WakeDuration) DeepCopyInto(out *WakeDuration) {
+	*out = *in
+	if in.WokenAt != nil {
+		in, out := &in.WokenAt, &out.WokenAt
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailableAt != nil {
+		in, out := &in.AvailableAt, &out.AvailableAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WakeDuration.
+func (in *WakeDuration) DeepCopy() *WakeDuration {
+	if in == nil {
+		return nil
+	}
+	out := new(WakeDuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WakeRateSpec) DeepCopyInto(out *WakeRateSpec) {
+	*out = *in
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WakeRateSpec.
+func (in *WakeRateSpec) DeepCopy() *WakeRateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WakeRateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// activeWindow returns a SnoozeWindow selecting app=api whose snooze started
+// an hour ago and ends in an hour.
+func activeWindow() *schedulingv1alpha1.SnoozeWindow {
+	start := time.Now().Add(-time.Hour)
+	return &schedulingv1alpha1.SnoozeWindow{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: schedulingv1alpha1.SnoozeWindowSpec{
+			LabelSelector: map[string]string{"app": "api"},
+			SnoozeSchedule: schedulingv1alpha1.SnoozeScheduleSpec{
+				StartTime: start.Format(utils.TimeLayout),
+				EndTime:   time.Now().Add(time.Hour).Format(utils.TimeLayout),
+				Date:      start.Format(utils.DateLayout),
+			},
+		},
+	}
+}
+
+// useFakeCluster points the commands at c and captures their output.
+func useFakeCluster(c client.Client) *bytes.Buffer {
+	output := &bytes.Buffer{}
+	previousConnect, previousOut := connect, out
+	connect = func(f *clusterFlags) (client.Client, string, error) {
+		namespace := f.namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		return c, namespace, nil
+	}
+	out = output
+	DeferCleanup(func() {
+		connect, out = previousConnect, previousOut
+	})
+	return output
+}
+
+var _ = Describe("Commands", func() {
+	var (
+		ctx        context.Context
+		c          client.Client
+		output     *bytes.Buffer
+		window     *schedulingv1alpha1.SnoozeWindow
+		deployment *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		window = activeWindow()
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api",
+				Namespace:   "default",
+				Labels:      map[string]string{"app": "api"},
+				Annotations: map[string]string{workloads.BackupReplicasKey: "3"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		}
+		web := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(window, deployment, web).Build()
+		output = useFakeCluster(c)
+	})
+
+	get := func(obj client.Object) client.Object {
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		return obj
+	}
+
+	Context("list", func() {
+		It("Should list the windows of the namespace with their state", func() {
+			Expect(runList(nil)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("NAME"))
+			Expect(output.String()).To(MatchRegexp(`nightly\s+Snoozed\s+Wake `))
+			Expect(output.String()).NotTo(ContainSubstring("NAMESPACE"))
+		})
+
+		It("Should list the windows of every namespace", func() {
+			Expect(runList([]string{"-A"})).To(Succeed())
+			Expect(output.String()).To(MatchRegexp(`NAMESPACE\s+NAME`))
+			Expect(output.String()).To(MatchRegexp(`default\s+nightly`))
+		})
+
+		It("Should report a namespace without windows", func() {
+			Expect(runList([]string{"-n", "other"})).To(Succeed())
+			Expect(output.String()).To(Equal("No SnoozeWindows found\n"))
+		})
+
+		It("Should reject arguments", func() {
+			Expect(runList([]string{"nightly"})).To(MatchError("list takes no arguments"))
+		})
+	})
+
+	Context("status", func() {
+		It("Should show the window and the resources it manages", func() {
+			Expect(runStatus([]string{"nightly"})).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("SnoozeWindow:     default/nightly"))
+			Expect(output.String()).To(ContainSubstring("State:            Snoozed"))
+			Expect(output.String()).To(MatchRegexp(`deployment\s+api\s+Snoozed\s+3`))
+			Expect(output.String()).NotTo(ContainSubstring("web"))
+		})
+
+		It("Should report a window that selects nothing", func() {
+			window.Spec.LabelSelector = map[string]string{"app": "none"}
+			Expect(c.Update(ctx, window)).To(Succeed())
+
+			Expect(runStatus([]string{"nightly"})).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("No resources match the window"))
+		})
+
+		It("Should fail for a missing window", func() {
+			Expect(runStatus([]string{"missing"})).NotTo(Succeed())
+		})
+	})
+
+	Context("wake", func() {
+		It("Should hold a window awake for a duration", func() {
+			Expect(runWake([]string{"nightly", "--for", "2h"})).To(Succeed())
+
+			until, active, err := utils.WakeOverrideUntil(get(window).GetAnnotations(), time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+			Expect(until).To(BeTemporally("~", time.Now().Add(2*time.Hour), 5*time.Second))
+			Expect(output.String()).To(HavePrefix("snoozewindow/nightly: wake until "))
+		})
+
+		It("Should hold a single workload awake", func() {
+			Expect(runWake([]string{"deployment/api", "--until", "2030-01-01T00:00:00Z"})).To(Succeed())
+
+			Expect(get(deployment).GetAnnotations()).To(HaveKeyWithValue(schedulingv1alpha1.WakeUntilAnnotation, "2030-01-01T00:00:00Z"))
+			Expect(output.String()).To(Equal("deployment/api: wake until 2030-01-01T00:00:00Z\n"))
+		})
+
+		It("Should clear a wake override", func() {
+			Expect(runWake([]string{"nightly", "--for", "1h"})).To(Succeed())
+			Expect(runWake([]string{"--clear", "nightly"})).To(Succeed())
+
+			Expect(get(window).GetAnnotations()).NotTo(HaveKey(schedulingv1alpha1.WakeUntilAnnotation))
+			Expect(output.String()).To(HaveSuffix("snoozewindow/nightly: wake override cleared\n"))
+		})
+	})
+
+	Context("snooze", func() {
+		It("Should snooze a window until a time", func() {
+			Expect(runSnooze([]string{"nightly", "--until", "2030-01-01T00:00:00Z"})).To(Succeed())
+
+			Expect(get(window).GetAnnotations()).To(HaveKeyWithValue(schedulingv1alpha1.SnoozeUntilAnnotation, "2030-01-01T00:00:00Z"))
+		})
+	})
+
+	DescribeTable("rejecting invalid overrides",
+		func(run func([]string) error, args []string, message string) {
+			Expect(run(args)).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("without a duration", runWake, []string{"nightly"}, "one of --for, --until or --clear is required"),
+		Entry("with both --for and --until", runWake, []string{"nightly", "--for", "1h", "--until", "2030-01-01T00:00:00Z"},
+			"only one of --for and --until may be set"),
+		Entry("with --clear and --for", runSnooze, []string{"nightly", "--clear", "--for", "1h"}, "--clear cannot be combined"),
+		Entry("with a negative duration", runWake, []string{"nightly", "--for", "-1h"}, "--for must be positive"),
+		Entry("with several targets", runWake, []string{"nightly", "other", "--for", "1h"}, "expected a single target"),
+		Entry("snoozing a workload", runSnooze, []string{"deployment/api", "--for", "1h"}, "snooze only applies to a SnoozeWindow"),
+		Entry("waking an unsupported kind", runWake, []string{"pod/api", "--for", "1h"}, "unsupported kind"),
+	)
+
+	Context("explain", func() {
+		It("Should explain a window selecting the workload", func() {
+			Expect(runExplain([]string{"deployment/api"})).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("nightly: selects deployment/api"))
+			Expect(output.String()).To(ContainSubstring("  - window is Snoozed"))
+		})
+
+		It("Should explain why a window does not select the workload", func() {
+			Expect(runExplain([]string{"web"})).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("nightly: does not select deployment/web"))
+			Expect(output.String()).To(ContainSubstring("  - label app=api does not match app=web"))
+		})
+
+		It("Should report a namespace without windows", func() {
+			Expect(c.Delete(ctx, window)).To(Succeed())
+
+			Expect(runExplain([]string{"deployment/api"})).To(Succeed())
+			Expect(output.String()).To(Equal("No SnoozeWindows in namespace default, deployment/api is never snoozed\n"))
+		})
+	})
+})
+
+var _ = DescribeTable("windowState",
+	func(mutate func(*schedulingv1alpha1.SnoozeWindow), validSchedule bool, want types.GomegaMatcher) {
+		window := activeWindow()
+		mutate(window)
+		var schedule *utils.Schedule
+		if validSchedule {
+			var err error
+			schedule, err = utils.ScheduleFor(window)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(windowState(window, schedule, time.Now())).To(want)
+	},
+	Entry("during the snooze", func(*schedulingv1alpha1.SnoozeWindow) {}, true, Equal("Snoozed")),
+	Entry("outside the snooze", func(w *schedulingv1alpha1.SnoozeWindow) {
+		w.Spec.SnoozeSchedule.Date = time.Now().AddDate(0, 0, -2).Format(utils.DateLayout)
+	}, true, Equal("Awake")),
+	Entry("while suspended", func(w *schedulingv1alpha1.SnoozeWindow) {
+		w.Spec.Suspended = true
+	}, true, Equal("Suspended")),
+	Entry("with a wake override", func(w *schedulingv1alpha1.SnoozeWindow) {
+		w.Annotations = map[string]string{schedulingv1alpha1.WakeUntilAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	}, true, HavePrefix("Awake, held awake until ")),
+	Entry("with a snooze override", func(w *schedulingv1alpha1.SnoozeWindow) {
+		w.Annotations = map[string]string{schedulingv1alpha1.SnoozeUntilAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	}, false, HavePrefix("Snoozed, on demand until ")),
+	Entry("with an invalid schedule", func(*schedulingv1alpha1.SnoozeWindow) {}, false, Equal("Invalid schedule")),
+	Entry("in dry-run mode", func(w *schedulingv1alpha1.SnoozeWindow) {
+		w.Spec.DryRun = true
+	}, true, Equal("Snoozed (dry run)")),
+)
+
+var _ = DescribeTable("mismatches",
+	func(resourceTypes []string, labels map[string]string, want []string) {
+		window := activeWindow()
+		window.Spec.LabelSelector = map[string]string{"app": "api", "tier": "backend"}
+		window.Spec.ResourceTypes = resourceTypes
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Labels: labels}}
+
+		Expect(mismatches(window, deployment, schedulingv1alpha1.ResourceTypeDeployment)).To(Equal(want))
+	},
+	Entry("a selected workload", nil, map[string]string{"app": "api", "tier": "backend"}, nil),
+	Entry("a missing label", nil, map[string]string{"app": "api"}, []string{"label tier=backend is missing"}),
+	Entry("a different label value", nil, map[string]string{"app": "web", "tier": "backend"},
+		[]string{"label app=api does not match app=web"}),
+	Entry("another resource type", []string{"statefulset"}, map[string]string{"app": "api", "tier": "backend"},
+		[]string{"resourceTypes [statefulset] do not include deployment"}),
+	Entry("every reason in order", []string{"job"}, map[string]string{"tier": "frontend"}, []string{
+		"resourceTypes [job] do not include deployment",
+		"label app=api is missing",
+		"label tier=backend does not match tier=frontend",
+	}),
+)
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	var cluster clusterFlags
+	cluster.register(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected a single workload such as deployment/api")
+	}
+	obj, name, err := parseWorkload(positional[0])
+	if err != nil {
+		return err
+	}
+
+	c, namespace, err := cluster.newClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+		return err
+	}
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := c.List(ctx, &snoozeWindows, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	kind := resourceType(obj)
+	reference := kind + "/" + name
+	if len(snoozeWindows.Items) == 0 {
+		fmt.Fprintf(out, "No SnoozeWindows in namespace %s, %s is never snoozed\n", namespace, reference)
+		return nil
+	}
+
+	now := time.Now()
+	for i := range snoozeWindows.Items {
+		snoozeWindow := &snoozeWindows.Items[i]
+		reasons := mismatches(snoozeWindow, obj, kind)
+		if len(reasons) > 0 {
+			fmt.Fprintf(out, "%s: does not select %s\n", snoozeWindow.Name, reference)
+			for _, reason := range reasons {
+				fmt.Fprintf(out, "  - %s\n", reason)
+			}
+			continue
+		}
+
+		fmt.Fprintf(out, "%s: selects %s\n", snoozeWindow.Name, reference)
+		schedule := loadSchedule(c, snoozeWindow)
+		fmt.Fprintf(out, "  - window is %s\n", windowState(snoozeWindow, schedule, now))
+		fmt.Fprintf(out, "  - next transition: %s\n", nextTransition(schedule, now))
+		if until, active, _ := utils.WakeOverrideUntil(obj.GetAnnotations(), now); active {
+			fmt.Fprintf(out, "  - %s is held awake until %s\n", reference, until.Local().Format(previewTimeLayout))
+		}
+	}
+	return nil
+}
+
+// mismatches lists why snoozeWindow does not select obj, in the order the
+// controller filters resources. It is empty when the window selects obj.
+func mismatches(snoozeWindow *schedulingv1alpha1.SnoozeWindow, obj client.Object, kind string) []string {
+	var reasons []string
+	if !adapter.ManagesResourceType(snoozeWindow, kind) {
+		reasons = append(reasons, fmt.Sprintf("resourceTypes [%s] do not include %s",
+			strings.Join(snoozeWindow.Spec.ResourceTypes, ", "), kind))
+	}
+
+	labels := obj.GetLabels()
+	keys := make([]string, 0, len(snoozeWindow.Spec.LabelSelector))
+	for key := range snoozeWindow.Spec.LabelSelector {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		want := snoozeWindow.Spec.LabelSelector[key]
+		got, exists := labels[key]
+		switch {
+		case !exists:
+			reasons = append(reasons, fmt.Sprintf("label %s=%s is missing", key, want))
+		case got != want:
+			reasons = append(reasons, fmt.Sprintf("label %s=%s does not match %s=%s", key, want, key, got))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var cluster clusterFlags
+	cluster.register(fs)
+	allNamespaces := fs.Bool("A", false, "List the SnoozeWindows of every namespace.")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("list takes no arguments")
+	}
+
+	c, namespace, err := cluster.newClient()
+	if err != nil {
+		return err
+	}
+	var opts []client.ListOption
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := c.List(context.Background(), &snoozeWindows, opts...); err != nil {
+		return err
+	}
+	if len(snoozeWindows.Items) == 0 {
+		fmt.Fprintln(out, "No SnoozeWindows found")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	if *allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tSTATE\tNEXT TRANSITION")
+	for i := range snoozeWindows.Items {
+		snoozeWindow := &snoozeWindows.Items[i]
+		if *allNamespaces {
+			fmt.Fprintf(w, "%s\t", snoozeWindow.Namespace)
+		}
+		schedule := loadSchedule(c, snoozeWindow)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", snoozeWindow.Name, windowState(snoozeWindow, schedule, now), nextTransition(schedule, now))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-snooze is a kubectl plugin for working with SnoozeWindows. Installed on
+// the PATH it runs as "kubectl snooze".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+var scheme = runtime.NewScheme()
+
+// out is where the commands print their output.
+var out io.Writer = os.Stdout
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(schedulingv1alpha1.AddToScheme(scheme))
+}
+
+// command is a kubectl-snooze subcommand.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "list", usage: "list [-A]                     List windows, their state and next transition", run: runList},
+	{name: "status", usage: "status WINDOW                 Show the resources a window manages and their state", run: runStatus},
+	{name: "wake", usage: "wake WINDOW|KIND/NAME         Hold a window or a workload awake (--for, --until or --clear)", run: runWake},
+	{name: "snooze", usage: "snooze WINDOW                 Snooze a window now (--for, --until or --clear)", run: runSnooze},
+	{name: "explain", usage: "explain KIND/NAME             Explain which windows select a workload and why", run: runExplain},
+	{name: "preview", usage: "preview WINDOW|-f FILE        List the next snoozes and wakes of a window", run: runPreview},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	i := slices.IndexFunc(commands, func(cmd command) bool { return cmd.name == name })
+	if i < 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	if err := commands[i].run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: kubectl snooze COMMAND [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "  "+cmd.usage)
+	}
+}
+
+// clusterFlags are the flags shared by the commands that talk to the cluster.
+type clusterFlags struct {
+	kubeconfig string
+	namespace  string
+}
+
+func (f *clusterFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the usual kubectl lookup.")
+	fs.StringVar(&f.namespace, "n", "", "Namespace of the SnoozeWindow. Defaults to the namespace of the current context.")
+	fs.StringVar(&f.namespace, "namespace", "", "Same as -n.")
+}
+
+// newClient returns a client for the cluster of the current context and the
+// namespace to work in.
+func (f *clusterFlags) newClient() (client.Client, string, error) {
+	return connect(f)
+}
+
+// connect connects to the cluster for newClient. Tests replace it with a fake
+// client.
+var connect = func(f *clusterFlags) (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = f.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+
+	namespace := f.namespace
+	if namespace == "" {
+		var err error
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	return c, namespace, nil
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments, which the flag package alone stops at.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
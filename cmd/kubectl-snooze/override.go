@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+func runWake(args []string) error {
+	return runOverride("wake", schedulingv1alpha1.WakeUntilAnnotation, true, args)
+}
+
+func runSnooze(args []string) error {
+	return runOverride("snooze", schedulingv1alpha1.SnoozeUntilAnnotation, false, args)
+}
+
+// runOverride sets or clears an override annotation. Wake overrides may target
+// a single workload as KIND/NAME, snooze overrides only a SnoozeWindow.
+func runOverride(name, annotation string, workloadTarget bool, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	var cluster clusterFlags
+	cluster.register(fs)
+	duration := fs.Duration("for", 0, "How long the override lasts, such as 2h.")
+	until := fs.String("until", "", "RFC 3339 time the override lasts until.")
+	remove := fs.Bool("clear", false, "Remove the override.")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected a single target to %s", name)
+	}
+
+	var value string
+	switch {
+	case *remove && (*duration != 0 || *until != ""):
+		return errors.New("--clear cannot be combined with --for or --until")
+	case *duration != 0 && *until != "":
+		return errors.New("only one of --for and --until may be set")
+	case *duration > 0:
+		value = time.Now().Add(*duration).UTC().Format(time.RFC3339)
+	case *duration < 0:
+		return errors.New("--for must be positive")
+	case *until != "":
+		parsed, err := utils.ParseTimestamp(*until)
+		if err != nil {
+			return err
+		}
+		value = parsed.UTC().Format(time.RFC3339)
+	case !*remove:
+		return errors.New("one of --for, --until or --clear is required")
+	}
+
+	c, namespace, err := cluster.newClient()
+	if err != nil {
+		return err
+	}
+
+	var obj client.Object = &schedulingv1alpha1.SnoozeWindow{}
+	objName, kind := positional[0], "snoozewindow"
+	if strings.Contains(positional[0], "/") {
+		if !workloadTarget {
+			return fmt.Errorf("%s only applies to a SnoozeWindow", name)
+		}
+		if obj, objName, err = parseWorkload(positional[0]); err != nil {
+			return err
+		}
+		kind = resourceType(obj)
+	}
+
+	ctx := context.Background()
+	if err := c.Get(ctx, types.NamespacedName{Name: objName, Namespace: namespace}, obj); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if *remove {
+		delete(annotations, annotation)
+	} else {
+		annotations[annotation] = value
+	}
+	obj.SetAnnotations(annotations)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+
+	if *remove {
+		fmt.Fprintf(out, "%s/%s: %s override cleared\n", kind, objName, name)
+	} else {
+		fmt.Fprintf(out, "%s/%s: %s until %s\n", kind, objName, name, value)
+	}
+	return nil
+}
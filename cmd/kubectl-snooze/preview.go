@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// previewTimeLayout shows the weekday, which is what a schedule is usually checked against.
+const previewTimeLayout = "Mon 2006-01-02 15:04 MST"
+
+func runPreview(args []string) error {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	var cluster clusterFlags
+	cluster.register(fs)
+	file := fs.String("f", "", "Preview the SnoozeWindow in this manifest instead of one in the cluster.")
+	count := fs.Int("count", 10, "Number of transitions to list.")
+	from := fs.String("from", "", "RFC 3339 time to preview from. Defaults to now.")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	snoozeWindow, err := loadWindow(&cluster, *file, positional)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if *from != "" {
+		if start, err = time.Parse(time.RFC3339, *from); err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+
+	schedule, err := utils.ScheduleFor(snoozeWindow)
+	if err != nil {
+		return err
+	}
+	// Calendars live in the cluster, even for a window read from a manifest
+	if len(snoozeWindow.Spec.Calendars) > 0 {
+		c, namespace, err := cluster.newClient()
+		if err != nil {
+			return err
+		}
+		if snoozeWindow.Namespace == "" {
+			snoozeWindow.Namespace = namespace
+		}
+		if err := utils.ApplyCalendars(context.Background(), c, snoozeWindow, schedule); err != nil {
+			return err
+		}
+	}
+	transitions := schedule.NextTransitions(start, *count)
+	if len(transitions) == 0 {
+		fmt.Fprintf(out, "SnoozeWindow %s has no upcoming transitions\n", snoozeWindow.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "ACTION\t%s\tUTC\n", schedule.Location())
+	for _, transition := range transitions {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", transition.Action,
+			transition.Time.In(schedule.Location()).Format(previewTimeLayout),
+			transition.Time.UTC().Format(previewTimeLayout))
+	}
+	return w.Flush()
+}
+
+// loadWindow reads a SnoozeWindow from a manifest when file is set, or from the
+// cluster by the name in args.
+func loadWindow(cluster *clusterFlags, file string, args []string) (*schedulingv1alpha1.SnoozeWindow, error) {
+	snoozeWindow := &schedulingv1alpha1.SnoozeWindow{}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, snoozeWindow); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		return snoozeWindow, nil
+	}
+
+	if len(args) != 1 {
+		return nil, errors.New("expected the name of a SnoozeWindow or -f FILE")
+	}
+	c, namespace, err := cluster.newClient()
+	if err != nil {
+		return nil, err
+	}
+	key := types.NamespacedName{Name: args[0], Namespace: namespace}
+	if err := c.Get(context.Background(), key, snoozeWindow); err != nil {
+		return nil, err
+	}
+	return snoozeWindow, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const previewManifest = `apiVersion: scheduling.codeacme.org/v1alpha1
+kind: SnoozeWindow
+metadata:
+  name: weeknights
+spec:
+  timezone: Europe/Berlin
+  snoozeSchedule:
+    cron: "0 19 * * MON-FRI"
+    duration: 12h
+`
+
+var _ = Describe("preview", func() {
+	var (
+		output   *bytes.Buffer
+		manifest string
+	)
+
+	BeforeEach(func() {
+		window := activeWindow()
+		output = useFakeCluster(fake.NewClientBuilder().WithScheme(scheme).WithObjects(window).Build())
+
+		manifest = filepath.Join(GinkgoT().TempDir(), "window.yaml")
+		Expect(os.WriteFile(manifest, []byte(previewManifest), 0o600)).To(Succeed())
+	})
+
+	lines := func() []string {
+		return strings.Split(strings.TrimSpace(output.String()), "\n")
+	}
+
+	It("Should list the next transitions of a manifest in its timezone and UTC", func() {
+		Expect(runPreview([]string{"-f", manifest, "--from", "2030-01-07T00:00:00Z", "--count", "3"})).To(Succeed())
+
+		Expect(lines()).To(HaveLen(4))
+		Expect(lines()[0]).To(MatchRegexp(`ACTION\s+Europe/Berlin\s+UTC`))
+		Expect(lines()[1]).To(MatchRegexp(`Snooze\s+Mon 2030-01-07 19:00 CET\s+Mon 2030-01-07 18:00 UTC`))
+		Expect(lines()[2]).To(MatchRegexp(`Wake\s+Tue 2030-01-08 07:00 CET\s+Tue 2030-01-08 06:00 UTC`))
+		Expect(lines()[3]).To(MatchRegexp(`Snooze\s+Tue 2030-01-08 19:00 CET`))
+	})
+
+	It("Should preview a window from the cluster", func() {
+		Expect(runPreview([]string{"nightly", "--count", "1"})).To(Succeed())
+
+		Expect(lines()).To(HaveLen(2))
+		Expect(lines()[1]).To(HavePrefix("Wake"))
+	})
+
+	It("Should report a window without upcoming transitions", func() {
+		Expect(runPreview([]string{"nightly", "--from", "2099-01-01T00:00:00Z"})).To(Succeed())
+		Expect(output.String()).To(Equal("SnoozeWindow nightly has no upcoming transitions\n"))
+	})
+
+	It("Should reject an invalid start time", func() {
+		Expect(runPreview([]string{"-f", manifest, "--from", "tomorrow"})).To(MatchError(ContainSubstring("invalid --from")))
+	})
+
+	It("Should reject a manifest with unknown fields", func() {
+		Expect(os.WriteFile(manifest, []byte(previewManifest+"  unknown: true\n"), 0o600)).To(Succeed())
+		Expect(runPreview([]string{"-f", manifest})).To(MatchError(ContainSubstring("reading " + manifest)))
+	})
+
+	It("Should require a window", func() {
+		Expect(runPreview(nil)).To(MatchError("expected the name of a SnoozeWindow or -f FILE"))
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// windowState describes what a SnoozeWindow is doing at now, in the order the
+// controller gives precedence to its settings. schedule is nil when the
+// window's schedule cannot be evaluated.
+func windowState(snoozeWindow *schedulingv1alpha1.SnoozeWindow, schedule *utils.Schedule, now time.Time) string {
+	var state string
+	switch {
+	case snoozeWindow.Spec.Suspended:
+		state = "Suspended"
+	case wakeOverride(snoozeWindow.GetAnnotations(), now) != "":
+		state = "Awake, " + wakeOverride(snoozeWindow.GetAnnotations(), now)
+	case manualSnooze(snoozeWindow, now) != "":
+		state = "Snoozed, " + manualSnooze(snoozeWindow, now)
+	case schedule == nil:
+		state = "Invalid schedule"
+	default:
+		state = "Awake"
+		if active, _ := schedule.Active(now); active {
+			state = "Snoozed"
+		}
+	}
+
+	if snoozeWindow.Spec.DryRun {
+		state += " (dry run)"
+	}
+	return state
+}
+
+// nextTransition describes the next transition of a schedule.
+func nextTransition(schedule *utils.Schedule, now time.Time) string {
+	if schedule == nil {
+		return "-"
+	}
+	transitions := schedule.NextTransitions(now, 1)
+	if len(transitions) == 0 {
+		return "-"
+	}
+	return transitions[0].Action + " " + transitions[0].Time.In(schedule.Location()).Format(previewTimeLayout)
+}
+
+// loadSchedule returns the schedule of a SnoozeWindow with its calendars, or
+// nil when it cannot be evaluated.
+func loadSchedule(c client.Reader, snoozeWindow *schedulingv1alpha1.SnoozeWindow) *utils.Schedule {
+	schedule, err := utils.LoadSchedule(context.Background(), c, snoozeWindow)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+func wakeOverride(annotations map[string]string, now time.Time) string {
+	if until, active, _ := utils.WakeOverrideUntil(annotations, now); active {
+		return "held awake until " + until.Local().Format(previewTimeLayout)
+	}
+	return ""
+}
+
+func manualSnooze(snoozeWindow *schedulingv1alpha1.SnoozeWindow, now time.Time) string {
+	until, _, _ := utils.SnoozeOverrideUntil(snoozeWindow.GetAnnotations(), now)
+	if suspendUntil := snoozeWindow.Spec.SuspendUntil; suspendUntil != nil && suspendUntil.After(until) {
+		until = suspendUntil.Time
+	}
+	if now.Before(until) {
+		return "on demand until " + until.Local().Format(previewTimeLayout)
+	}
+	return ""
+}
+
+// newWorkload returns an empty object of a kind a SnoozeWindow manages.
+func newWorkload(kind string) (client.Object, error) {
+	switch strings.ToLower(kind) {
+	case schedulingv1alpha1.ResourceTypeDeployment, "deployments", "deploy":
+		return &appsv1.Deployment{}, nil
+	case schedulingv1alpha1.ResourceTypeStatefulSet, "statefulsets", "sts":
+		return &appsv1.StatefulSet{}, nil
+	case schedulingv1alpha1.ResourceTypeJob, "jobs":
+		return &batchv1.Job{}, nil
+	case schedulingv1alpha1.ResourceTypeCronJob, "cronjobs", "cj":
+		return &batchv1.CronJob{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, expected deployment, statefulset, job or cronjob", kind)
+	}
+}
+
+// resourceType returns the resource type a SnoozeWindow uses for obj.
+func resourceType(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return schedulingv1alpha1.ResourceTypeDeployment
+	case *appsv1.StatefulSet:
+		return schedulingv1alpha1.ResourceTypeStatefulSet
+	case *batchv1.Job:
+		return schedulingv1alpha1.ResourceTypeJob
+	default:
+		return schedulingv1alpha1.ResourceTypeCronJob
+	}
+}
+
+// parseWorkload splits a KIND/NAME reference. A bare name is a Deployment.
+func parseWorkload(reference string) (client.Object, string, error) {
+	kind, name, found := strings.Cut(reference, "/")
+	if !found {
+		kind, name = schedulingv1alpha1.ResourceTypeDeployment, reference
+	}
+	obj, err := newWorkload(kind)
+	if err != nil {
+		return nil, "", err
+	}
+	return obj, name, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/pkg/types"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	var cluster clusterFlags
+	cluster.register(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	snoozeWindow, err := loadWindow(&cluster, "", positional)
+	if err != nil {
+		return err
+	}
+	c, _, err := cluster.newClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	schedule := loadSchedule(c, snoozeWindow)
+	fmt.Fprintf(out, "SnoozeWindow:     %s/%s\n", snoozeWindow.Namespace, snoozeWindow.Name)
+	fmt.Fprintf(out, "State:            %s\n", windowState(snoozeWindow, schedule, now))
+	fmt.Fprintf(out, "Next transition:  %s\n", nextTransition(schedule, now))
+	if savings := snoozeWindow.Status.Savings; savings != nil {
+		fmt.Fprintf(out, "Saved:            %s core hours, %s GiB hours", savings.CPUCoreHours, savings.MemoryGiBHours)
+		if savings.EstimatedCost != "" {
+			fmt.Fprintf(out, ", estimated cost %s", savings.EstimatedCost)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+
+	resourceManager, err := adapter.ForWindow(context.Background(), c, snoozeWindow)
+	if err != nil {
+		return err
+	}
+	resources := resourceManager.Resources()
+	if len(resources) == 0 {
+		fmt.Fprintln(out, "No resources match the window")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tSTATE\tRESTORES TO")
+	for _, resource := range resources {
+		restores := resource.GetAnnotations()[workloads.BackupReplicasKey]
+		if restores == "" {
+			restores = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", resource.GetResourceType(), resource.GetName(), resourceState(resource, now), restores)
+	}
+	return w.Flush()
+}
+
+// resourceState describes whether a resource managed by a window is snoozed.
+func resourceState(resource types.SnoozableResource, now time.Time) string {
+	annotations := resource.GetAnnotations()
+	if until, active, _ := utils.WakeOverrideUntil(annotations, now); active {
+		return "Awake, held awake until " + until.Local().Format(previewTimeLayout)
+	}
+	if !resource.IsSnoozed() {
+		return "Awake"
+	}
+	if _, overridden := annotations[workloads.OverrideKey]; overridden {
+		return "Awake, scaled up during the window"
+	}
+	if resource.IsDrifted() {
+		return "Drifted, scaled up outside of kube-snooze"
+	}
+	return "Snoozed"
+}
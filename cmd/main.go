@@ -21,12 +21,14 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -39,6 +41,8 @@ import (
 
 	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
 	"codeacme.org/kube-snooze/internal/controller"
+	webhookappsv1 "codeacme.org/kube-snooze/internal/webhook/apps/v1"
+	webhookschedulingv1alpha1 "codeacme.org/kube-snooze/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -63,6 +67,13 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var defaultTimezone string
+	var activatorService string
+	var scaleGuardPolicy string
+	var pricingConfigMap string
+	var prometheusURL string
+	var notificationHosts string
+	var wakeLimits controller.WakeLimits
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +92,29 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&defaultTimezone, "default-timezone", "UTC",
+		"The timezone assigned to SnoozeWindows that do not specify one.")
+	flag.StringVar(&activatorService, "activator-service", "kube-snooze-system/kube-snooze-activator",
+		"The activator Service, as namespace/name, assigned to SnoozeWindows that use the activator without naming one.")
+	flag.StringVar(&scaleGuardPolicy, "scale-guard-policy", "",
+		"If set, manual scale-ups of workloads snoozed by an active SnoozeWindow are either rejected (reject) "+
+			"or admitted and marked as an override (override). Leave empty to disable the scale guard webhook.")
+	flag.StringVar(&pricingConfigMap, "pricing-configmap", "",
+		"The namespace/name of a ConfigMap with the "+controller.PricePerCoreHourKey+" and "+
+			controller.PricePerGiBHourKey+" used to estimate the cost saved by each SnoozeWindow. "+
+			"Leave empty to only report saved core and GiB hours.")
+	flag.StringVar(&prometheusURL, "prometheus-url", "",
+		"The Prometheus server queried by SnoozeWindows that snooze idle workloads with the Prometheus source.")
+	flag.StringVar(&notificationHosts, "notification-hosts", "hooks.slack.com",
+		"The comma-separated hosts SnoozeWindows may send notifications to. "+
+			"A host starting with *. allows every host of that domain. Leave empty to send no notifications.")
+	flag.IntVar(&wakeLimits.ResourcesPerMinute, "wake-resources-per-minute", 0,
+		"The most resources woken a minute across all SnoozeWindows, evenly spaced. 0 disables the limit.")
+	flag.IntVar(&wakeLimits.NamespaceResourcesPerMinute, "namespace-wake-resources-per-minute", 0,
+		"The most resources woken a minute in each namespace, evenly spaced. 0 disables the limit.")
+	flag.DurationVar(&wakeLimits.Jitter, "wake-jitter", 0,
+		"Delays each scheduled wake by up to this long, by an offset fixed for each SnoozeWindow. "+
+			"SnoozeWindows can set their own in spec.wakeRate.jitter.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,6 +123,23 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	var pricingConfigMapKey types.NamespacedName
+	if pricingConfigMap != "" {
+		namespace, name, found := strings.Cut(pricingConfigMap, "/")
+		if !found || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --pricing-configmap, expected namespace/name", "value", pricingConfigMap)
+			os.Exit(1)
+		}
+		pricingConfigMapKey = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	var notificationHostList []string
+	for _, host := range strings.Split(notificationHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			notificationHostList = append(notificationHostList, host)
+		}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -203,12 +254,33 @@ func main() {
 	}
 
 	if err := (&controller.SnoozeWindowReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("snoozewindow-controller"),
+		APIReader: mgr.GetAPIReader(),
+
+		PricingConfigMap:  pricingConfigMapKey,
+		WakeLimits:        &wakeLimits,
+		PrometheusURL:     prometheusURL,
+		NotificationHosts: notificationHostList,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SnoozeWindow")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookschedulingv1alpha1.SetupSnoozeWindowWebhookWithManager(mgr, defaultTimezone, activatorService); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SnoozeWindow")
+			os.Exit(1)
+		}
+		if scaleGuardPolicy != "" {
+			if err := webhookappsv1.SetupScaleGuardWebhookWithManager(mgr,
+				webhookappsv1.ScaleGuardPolicy(scaleGuardPolicy)); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "ScaleGuard")
+				os.Exit(1)
+			}
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
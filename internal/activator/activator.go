@@ -0,0 +1,282 @@
+package activator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/service"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+const (
+	// DefaultIdleTimeout is how long woken workloads stay up after the last
+	// request when their window does not set spec.activator.idleTimeout.
+	DefaultIdleTimeout = 15 * time.Minute
+	// DefaultWakeTimeout is how long a request waits for the workloads behind
+	// its Service to become available.
+	DefaultWakeTimeout = 5 * time.Minute
+	// DefaultPollInterval is how often the workloads are checked on while a
+	// request waits for them.
+	DefaultPollInterval = time.Second
+)
+
+// Activator receives the requests sent to snoozed Services, on the activator
+// port each Service port is pointed at, see Listeners. A request wakes
+// the workloads behind its Service by setting their wake-until annotation,
+// which the SnoozeWindow controller honors like any other wake override, and
+// is held until they are available, then forwarded to the Service's backend.
+// Every request pushes the annotation further out, so the workloads are
+// snoozed again once no request came in for the idle timeout.
+type Activator struct {
+	Client       client.Client
+	WakeTimeout  time.Duration
+	PollInterval time.Duration
+	// Backend returns the address requests for port of the snoozed Service
+	// svc are forwarded to. It defaults to the Service's backend Service.
+	Backend func(svc *corev1.Service, port int32) string
+}
+
+// ServeHTTP wakes the workloads behind the Service the request was sent to and
+// forwards the request to them once they are available.
+func (a *Activator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	logger := logf.FromContext(ctx).WithValues("host", req.Host)
+
+	localAddr, _ := ctx.Value(http.LocalAddrContextKey).(*net.TCPAddr)
+	if localAddr == nil {
+		http.Error(w, "unknown destination", http.StatusBadGateway)
+		return
+	}
+	svc, port, err := a.resolve(ctx, int32(localAddr.Port))
+	if err != nil {
+		logger.Info("Failed to resolve the Service of a request", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	logger = logger.WithValues("service", client.ObjectKeyFromObject(svc))
+
+	targets, err := a.workloads(ctx, svc)
+	if err != nil {
+		logger.Error(err, "Failed to find the workloads behind the Service")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if len(targets) == 0 {
+		http.Error(w, fmt.Sprintf("no workload behind service %s/%s", svc.Namespace, svc.Name), http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := a.wake(ctx, svc, targets); err != nil {
+		logger.Error(err, "Failed to wake the workloads behind the Service")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if err := a.waitAvailable(ctx, targets); err != nil {
+		logger.Info("Workloads did not become available in time", "error", err.Error())
+		http.Error(w, fmt.Sprintf("service %s/%s did not wake in time", svc.Namespace, svc.Name), http.StatusGatewayTimeout)
+		return
+	}
+
+	backend := a.Backend
+	if backend == nil {
+		backend = backendService
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(&url.URL{Scheme: "http", Host: backend(svc, port)})
+			pr.SetXForwarded()
+			// The workloads see the Host the client asked for
+			pr.Out.Host = pr.In.Host
+		},
+	}
+	proxy.ServeHTTP(w, req)
+}
+
+// backendService returns the address of the backend Service of svc.
+func backendService(svc *corev1.Service, port int32) string {
+	return net.JoinHostPort(service.BackendName(svc.Name)+"."+svc.Namespace+".svc", strconv.Itoa(int(port)))
+}
+
+// resolve returns the snoozed Service whose EndpointSlice points one of its
+// ports at the activator port, and the number of that Service port.
+func (a *Activator) resolve(ctx context.Context, activatorPort int32) (*corev1.Service, int32, error) {
+	var slices discoveryv1.EndpointSliceList
+	if err := a.Client.List(ctx, &slices, client.MatchingLabels{discoveryv1.LabelManagedBy: service.ManagedBy}); err != nil {
+		return nil, 0, err
+	}
+
+	var matches []discoveryv1.EndpointSlice
+	var portName string
+	for _, slice := range slices.Items {
+		for _, port := range slice.Ports {
+			if port.Port != nil && *port.Port == activatorPort {
+				matches = append(matches, slice)
+				portName = ptr.Deref(port.Name, "")
+			}
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, 0, fmt.Errorf("no snoozed service on activator port %d", activatorPort)
+	case len(matches) > 1:
+		return nil, 0, fmt.Errorf("activator port %d is used by %d snoozed services", activatorPort, len(matches))
+	}
+
+	var svc corev1.Service
+	key := client.ObjectKey{Namespace: matches[0].Namespace, Name: matches[0].Labels[discoveryv1.LabelServiceName]}
+	if err := a.Client.Get(ctx, key, &svc); err != nil {
+		return nil, 0, err
+	}
+	if _, snoozed := svc.Annotations[service.BackupSelectorKey]; !snoozed {
+		return nil, 0, fmt.Errorf("service %s is not snoozed", key)
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.Name == portName {
+			return &svc, port.Port, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("service %s has no port %q", key, portName)
+}
+
+// workloads returns the Deployments and StatefulSets whose pods the snoozed
+// Service svc selected.
+func (a *Activator) workloads(ctx context.Context, svc *corev1.Service) ([]snoozetypes.ReplicatedResource, error) {
+	backup, _, err := service.BackupSelector(svc)
+	if err != nil {
+		return nil, err
+	}
+	if len(backup) == 0 {
+		return nil, nil
+	}
+	selector := labels.SelectorFromSet(backup)
+
+	var targets []snoozetypes.ReplicatedResource
+	var deployments appsv1.DeploymentList
+	if err := a.Client.List(ctx, &deployments, client.InNamespace(svc.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		if selector.Matches(labels.Set(deployments.Items[i].Spec.Template.Labels)) {
+			targets = append(targets, workloads.NewDeploymentAdapter(&deployments.Items[i]))
+		}
+	}
+	var statefulSets appsv1.StatefulSetList
+	if err := a.Client.List(ctx, &statefulSets, client.InNamespace(svc.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		if selector.Matches(labels.Set(statefulSets.Items[i].Spec.Template.Labels)) {
+			targets = append(targets, workloads.NewStatefulSetAdapter(&statefulSets.Items[i]))
+		}
+	}
+	return targets, nil
+}
+
+// wake sets the wake-until annotation of the snoozed targets to the idle
+// timeout from now. Targets already held awake for most of the idle timeout
+// are left alone, so a steady stream of requests does not patch them on every
+// request.
+func (a *Activator) wake(ctx context.Context, svc *corev1.Service, targets []snoozetypes.ReplicatedResource) error {
+	idleTimeout := a.idleTimeout(ctx, svc)
+	now := time.Now()
+	until := now.Add(idleTimeout).UTC().Format(time.RFC3339)
+
+	for _, target := range targets {
+		annotations := target.GetAnnotations()
+		_, held := annotations[schedulingv1alpha1.WakeUntilAnnotation]
+		if !target.IsSnoozed() && !held {
+			continue
+		}
+		if current, _, _ := utils.WakeOverrideUntil(annotations, now); current.Sub(now) >= idleTimeout*3/4 {
+			continue
+		}
+		if err := target.Annotate(ctx, a.Client, schedulingv1alpha1.WakeUntilAnnotation, until); err != nil {
+			return err
+		}
+		logf.FromContext(ctx).Info("Holding workload awake for requests", "resource", target.GetResourceType()+"/"+target.GetName(),
+			"namespace", target.GetNamespace(), "until", until)
+	}
+	return nil
+}
+
+// idleTimeout returns the idle timeout of the window that snoozed svc.
+func (a *Activator) idleTimeout(ctx context.Context, svc *corev1.Service) time.Duration {
+	owner := svc.Annotations[schedulingv1alpha1.SnoozedByAnnotation]
+	if owner == "" {
+		return DefaultIdleTimeout
+	}
+	var window schedulingv1alpha1.SnoozeWindow
+	if err := a.Client.Get(ctx, client.ObjectKey{Namespace: svc.Namespace, Name: owner}, &window); err != nil {
+		return DefaultIdleTimeout
+	}
+	if window.Spec.Activator == nil || window.Spec.Activator.IdleTimeout == nil {
+		return DefaultIdleTimeout
+	}
+	return window.Spec.Activator.IdleTimeout.Duration
+}
+
+// waitAvailable waits until each of targets has an available replica.
+func (a *Activator) waitAvailable(ctx context.Context, targets []snoozetypes.ReplicatedResource) error {
+	timeout := a.WakeTimeout
+	if timeout <= 0 {
+		timeout = DefaultWakeTimeout
+	}
+	interval := a.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		for _, target := range targets {
+			available, err := a.available(ctx, target)
+			if err != nil || !available {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// available fetches target again and reports whether it has an available
+// replica.
+func (a *Activator) available(ctx context.Context, target snoozetypes.ReplicatedResource) (bool, error) {
+	key := client.ObjectKey{Namespace: target.GetNamespace(), Name: target.GetName()}
+	var current snoozetypes.ReplicatedResource
+	switch target.GetResourceType() {
+	case schedulingv1alpha1.ResourceTypeDeployment:
+		var deployment appsv1.Deployment
+		if err := a.Client.Get(ctx, key, &deployment); err != nil {
+			return false, err
+		}
+		current = workloads.NewDeploymentAdapter(&deployment)
+	case schedulingv1alpha1.ResourceTypeStatefulSet:
+		var statefulSet appsv1.StatefulSet
+		if err := a.Client.Get(ctx, key, &statefulSet); err != nil {
+			return false, err
+		}
+		current = workloads.NewStatefulSetAdapter(&statefulSet)
+	default:
+		return false, errors.New("unsupported workload " + target.GetResourceType())
+	}
+	available, _ := current.Availability()
+	return available > 0, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activator
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/service"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+var _ = Describe("Activator", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		svc        *corev1.Service
+		slice      *discoveryv1.EndpointSlice
+		deployment *appsv1.Deployment
+		window     *schedulingv1alpha1.SnoozeWindow
+		backend    *httptest.Server
+		hosts      []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(schedulingv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api",
+				Namespace: "staging",
+				Annotations: map[string]string{
+					service.BackupSelectorKey:              `{"app":"api"}`,
+					schedulingv1alpha1.SnoozedByAnnotation: "nightly",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.0.0.10",
+				Ports:     []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}
+		slice = activatorSlice(svc, 20001)
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api",
+				Namespace:   "staging",
+				Annotations: map[string]string{workloads.BackupReplicasKey: "2"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](0),
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}}},
+			},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 2},
+		}
+		window = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "staging"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				Activator: &schedulingv1alpha1.ActivatorSpec{IdleTimeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			},
+		}
+
+		hosts = nil
+		backend = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hosts = append(hosts, r.Host)
+			_, _ = io.WriteString(w, "hello from api")
+		}))
+		DeferCleanup(backend.Close)
+	})
+
+	serve := func(activator *Activator, host string, port int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, &net.TCPAddr{Port: port}))
+		recorder := httptest.NewRecorder()
+		activator.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	newActivator := func(objects ...client.Object) *Activator {
+		backendURL, err := url.Parse(backend.URL)
+		Expect(err).NotTo(HaveOccurred())
+		return &Activator{
+			Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			WakeTimeout:  200 * time.Millisecond,
+			PollInterval: 10 * time.Millisecond,
+			Backend: func(*corev1.Service, int32) string {
+				return backendURL.Host
+			},
+		}
+	}
+
+	It("Should hold a snoozed workload awake and forward the request once it is available", func() {
+		activator := newActivator(svc, slice, deployment, window)
+
+		recorder := serve(activator, "api.staging", 20001)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("hello from api"))
+		Expect(hosts).To(ConsistOf("api.staging"))
+
+		var updated appsv1.Deployment
+		Expect(activator.Client.Get(ctx, client.ObjectKeyFromObject(deployment), &updated)).To(Succeed())
+		until, held, err := utils.WakeOverrideUntil(updated.Annotations, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(held).To(BeTrue())
+		Expect(until).To(BeTemporally("~", time.Now().Add(10*time.Minute), 5*time.Second))
+	})
+
+	It("Should resolve the Service by the activator port whatever the Host", func() {
+		activator := newActivator(svc, slice, deployment, window)
+
+		Expect(serve(activator, "api.example.com", 20001).Code).To(Equal(http.StatusOK))
+		Expect(serve(activator, "10.0.0.10:80", 20001).Code).To(Equal(http.StatusOK))
+		Expect(serve(activator, "api.staging", 20002).Code).To(Equal(http.StatusBadGateway))
+		Expect(hosts).To(ConsistOf("api.example.com", "10.0.0.10:80"))
+	})
+
+	It("Should tell apart Services of the same name in two namespaces", func() {
+		production := svc.DeepCopy()
+		production.Namespace = "production"
+		productionDeployment := deployment.DeepCopy()
+		productionDeployment.Namespace = "production"
+		activator := newActivator(svc, slice, deployment, window,
+			production, activatorSlice(production, 20002), productionDeployment)
+
+		Expect(serve(activator, "api", 20002).Code).To(Equal(http.StatusOK))
+
+		var updated appsv1.Deployment
+		Expect(activator.Client.Get(ctx, client.ObjectKeyFromObject(productionDeployment), &updated)).To(Succeed())
+		Expect(updated.Annotations).To(HaveKey(schedulingv1alpha1.WakeUntilAnnotation))
+		Expect(activator.Client.Get(ctx, client.ObjectKeyFromObject(deployment), &updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(schedulingv1alpha1.WakeUntilAnnotation))
+	})
+
+	It("Should time out when the workload does not become available", func() {
+		deployment.Status.AvailableReplicas = 0
+		activator := newActivator(svc, slice, deployment, window)
+
+		Expect(serve(activator, "api", 20001).Code).To(Equal(http.StatusGatewayTimeout))
+		Expect(hosts).To(BeEmpty())
+	})
+})
+
+// activatorSlice returns the EndpointSlice pointing the port of svc at the
+// activator port.
+func activatorSlice(svc *corev1.Service, port int32) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name + "-kube-snooze",
+			Namespace: svc.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: svc.Name,
+				discoveryv1.LabelManagedBy:   service.ManagedBy,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To(port)}},
+	}
+}
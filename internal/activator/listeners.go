@@ -0,0 +1,110 @@
+package activator
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"codeacme.org/kube-snooze/internal/controller/adapter/service"
+)
+
+// DefaultResync is how often Listeners checks which activator ports the
+// snoozed Services are pointed at.
+const DefaultResync = time.Second
+
+// Listeners serves Handler on every activator port a snoozed Service is
+// pointed at, opening a listener when a Service is snoozed and closing it once
+// the Service is woken.
+type Listeners struct {
+	Client  client.Reader
+	Handler http.Handler
+	// Host is the address the listeners bind to, all interfaces when empty.
+	Host   string
+	Resync time.Duration
+	// BaseContext returns the base context of the requests, as in http.Server.
+	BaseContext func(net.Listener) context.Context
+}
+
+var _ manager.Runnable = &Listeners{}
+var _ manager.LeaderElectionRunnable = &Listeners{}
+
+// NeedLeaderElection returns false, every activator replica serves requests.
+func (l *Listeners) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the activator ports until ctx is done.
+func (l *Listeners) Start(ctx context.Context) error {
+	resync := l.Resync
+	if resync <= 0 {
+		resync = DefaultResync
+	}
+
+	servers := make(map[int32]*http.Server)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		l.sync(ctx, servers)
+	}, resync)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, server := range servers {
+		_ = server.Shutdown(shutdownCtx)
+	}
+	return nil
+}
+
+// sync opens a listener for every activator port in use and closes the ones
+// no longer used.
+func (l *Listeners) sync(ctx context.Context, servers map[int32]*http.Server) {
+	logger := logf.FromContext(ctx)
+
+	var slices discoveryv1.EndpointSliceList
+	if err := l.Client.List(ctx, &slices, client.MatchingLabels{discoveryv1.LabelManagedBy: service.ManagedBy}); err != nil {
+		logger.Error(err, "Failed to list the EndpointSlices of snoozed Services")
+		return
+	}
+	wanted := make(map[int32]bool)
+	for _, slice := range slices.Items {
+		for _, port := range slice.Ports {
+			if port.Port != nil && *port.Port >= service.FirstActivatorPort && *port.Port <= service.LastActivatorPort {
+				wanted[*port.Port] = true
+			}
+		}
+	}
+
+	for port, server := range servers {
+		if !wanted[port] {
+			_ = server.Close()
+			delete(servers, port)
+		}
+	}
+	for port := range wanted {
+		if _, serving := servers[port]; serving {
+			continue
+		}
+		listener, err := net.Listen("tcp", net.JoinHostPort(l.Host, strconv.Itoa(int(port))))
+		if err != nil {
+			logger.Error(err, "Failed to listen on an activator port", "port", port)
+			continue
+		}
+		server := &http.Server{
+			Handler:           l.Handler,
+			ReadHeaderTimeout: 30 * time.Second,
+			BaseContext:       l.BaseContext,
+		}
+		servers[port] = server
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				logger.Error(err, "Activator port stopped serving", "port", port)
+			}
+		}()
+	}
+}
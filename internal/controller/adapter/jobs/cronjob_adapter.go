@@ -4,7 +4,13 @@ import (
 	"context"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 type CronJobAdapter struct {
@@ -23,6 +29,10 @@ func (c *CronJobAdapter) GetNamespace() string {
 	return c.cronjob.Namespace
 }
 
+func (c *CronJobAdapter) GetLabels() map[string]string {
+	return c.cronjob.Labels
+}
+
 func (c *CronJobAdapter) GetAnnotations() map[string]string {
 	return c.cronjob.Annotations
 }
@@ -32,19 +42,43 @@ func (c *CronJobAdapter) SetAnnotations(annotations map[string]string) {
 }
 
 func (c *CronJobAdapter) IsSnoozed() bool {
-	return *c.cronjob.Spec.Suspend
+	return ptr.Deref(c.cronjob.Spec.Suspend, false)
 }
 
-func (c *CronJobAdapter) Snooze(ctx context.Context, r client.Client) error {
-	isSuspended := true
-	c.cronjob.Spec.Suspend = &isSuspended
-	return r.Update(ctx, c.cronjob)
+func (c *CronJobAdapter) IsDrifted() bool {
+	return false
+}
+
+// IsSettled returns true, suspending a cronjob only affects its future runs.
+func (c *CronJobAdapter) IsSettled() bool {
+	return true
+}
+
+func (c *CronJobAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	return utils.PatchWithRetry(ctx, r, c.cronjob, func() error {
+		c.cronjob.Spec.Suspend = ptr.To(true)
+		metav1.SetMetaDataAnnotation(&c.cronjob.ObjectMeta, schedulingv1alpha1.SnoozedByAnnotation, owner)
+		return nil
+	})
 }
 
 func (c *CronJobAdapter) Wake(ctx context.Context, r client.Client) error {
-	isSuspended := false
-	c.cronjob.Spec.Suspend = &isSuspended
-	return r.Update(ctx, c.cronjob)
+	return utils.PatchWithRetry(ctx, r, c.cronjob, func() error {
+		c.cronjob.Spec.Suspend = ptr.To(false)
+		delete(c.cronjob.Annotations, schedulingv1alpha1.SnoozedByAnnotation)
+		return nil
+	})
+}
+
+// SnoozedRequests returns nil, savings are only estimated for scaled down replicas.
+func (c *CronJobAdapter) SnoozedRequests() corev1.ResourceList {
+	return nil
+}
+
+// Annotate sets the annotation key of the cronjob to value, or removes it when
+// value is empty.
+func (c *CronJobAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, c.cronjob, key, value)
 }
 
 func (c *CronJobAdapter) GetResourceType() string {
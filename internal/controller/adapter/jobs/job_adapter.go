@@ -4,7 +4,13 @@ import (
 	"context"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 type JobAdapter struct {
@@ -23,6 +29,10 @@ func (j *JobAdapter) GetNamespace() string {
 	return j.job.Namespace
 }
 
+func (j *JobAdapter) GetLabels() map[string]string {
+	return j.job.Labels
+}
+
 func (j *JobAdapter) GetAnnotations() map[string]string {
 	return j.job.Annotations
 }
@@ -35,16 +45,44 @@ func (j *JobAdapter) IsSnoozed() bool {
 	return j.job.Spec.Suspend != nil && *j.job.Spec.Suspend
 }
 
-func (j *JobAdapter) Snooze(ctx context.Context, r client.Client) error {
-	isSuspended := true
-	j.job.Spec.Suspend = &isSuspended
-	return r.Update(ctx, j.job)
+func (j *JobAdapter) IsDrifted() bool {
+	return false
+}
+
+// IsSettled reports whether a suspended job has no pods left running. A resumed
+// job is settled right away, its pods run to completion.
+func (j *JobAdapter) IsSettled() bool {
+	if j.IsSnoozed() {
+		return j.job.Status.Active == 0
+	}
+	return true
+}
+
+func (j *JobAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	return utils.PatchWithRetry(ctx, r, j.job, func() error {
+		j.job.Spec.Suspend = ptr.To(true)
+		metav1.SetMetaDataAnnotation(&j.job.ObjectMeta, schedulingv1alpha1.SnoozedByAnnotation, owner)
+		return nil
+	})
 }
 
 func (j *JobAdapter) Wake(ctx context.Context, r client.Client) error {
-	isSuspended := false
-	j.job.Spec.Suspend = &isSuspended
-	return r.Update(ctx, j.job)
+	return utils.PatchWithRetry(ctx, r, j.job, func() error {
+		j.job.Spec.Suspend = ptr.To(false)
+		delete(j.job.Annotations, schedulingv1alpha1.SnoozedByAnnotation)
+		return nil
+	})
+}
+
+// SnoozedRequests returns nil, savings are only estimated for scaled down replicas.
+func (j *JobAdapter) SnoozedRequests() corev1.ResourceList {
+	return nil
+}
+
+// Annotate sets the annotation key of the job to value, or removes it when
+// value is empty.
+func (j *JobAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, j.job, key, value)
 }
 
 func (j *JobAdapter) GetResourceType() string {
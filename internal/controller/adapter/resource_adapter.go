@@ -2,14 +2,70 @@ package adapter
 
 import (
 	"context"
+	"slices"
+	"time"
 
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/service"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/metrics"
 	"codeacme.org/kube-snooze/internal/pkg/types"
+	"codeacme.org/kube-snooze/internal/utils"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// WakeLimiter paces the wakes of a ResourceManager.
+type WakeLimiter interface {
+	// Reserve takes the slot of a wake and returns zero when a resource may be
+	// woken at now, or returns how long until the next slot.
+	Reserve(now time.Time) time.Duration
+}
+
+// Notifier is told when a snooze or wake of a ResourceManager finishes.
+type Notifier interface {
+	// Notify reports a snooze or wake that finished, with the resources it
+	// left snoozed or awake, or the error it failed with.
+	Notify(ctx context.Context, action string, resources []types.SnoozableResource, err error)
+}
+
 type ResourceManager struct {
 	resources []types.SnoozableResource
+
+	// Owner is the name of the window rm acts for, recorded on the resources
+	// it snoozes.
+	Owner string
+
+	// Tiers are the window's tiers, which order the resources on snooze and wake.
+	Tiers []schedulingv1alpha1.SnoozeTier
+
+	// WakeLimiter paces WakeTiers, which stops at the first resource it holds
+	// back. WakeAll is never held back.
+	WakeLimiter  WakeLimiter
+	throttledFor time.Duration
+
+	// WokenByWake reports whether an earlier call of WakeTiers in the wake in
+	// progress scaled a resource up. WakeTiers only waits on those, and on the
+	// ones it woke itself. Without it every awake resource is waited on.
+	WokenByWake func(types.SnoozableResource) bool
+	// WakeDeadline ends the wait of WakeTiers on a tier that is not ready, the
+	// next tier is then woken regardless. Zero waits without a deadline.
+	WakeDeadline time.Time
+	overdue      []string
+
+	woken   []types.SnoozableResource
+	snoozed []types.SnoozableResource
+
+	// Notifier is told when SnoozeAll, WakeAll, SnoozeTiers or WakeTiers
+	// finishes having changed a resource, or fails. It is not told in dry-run
+	// mode.
+	Notifier Notifier
+
+	// DryRun sends every snooze and wake as a server-side dry run, which
+	// validates the change without persisting it.
+	DryRun        bool
+	dryRunChanges []schedulingv1alpha1.DryRunChange
 }
 
 func NewResourceManager() *ResourceManager {
@@ -22,22 +78,258 @@ func (rm *ResourceManager) AddResource(resource types.SnoozableResource) {
 	rm.resources = append(rm.resources, resource)
 }
 
+// Resources returns the resources managed by rm.
+func (rm *ResourceManager) Resources() []types.SnoozableResource {
+	return rm.resources
+}
+
+// Exclude removes the resources for which exclude returns true from rm and
+// returns them.
+func (rm *ResourceManager) Exclude(exclude func(types.SnoozableResource) bool) []types.SnoozableResource {
+	var excluded []types.SnoozableResource
+	rm.resources = slices.DeleteFunc(rm.resources, func(resource types.SnoozableResource) bool {
+		if exclude(resource) {
+			excluded = append(excluded, resource)
+			return true
+		}
+		return false
+	})
+	return excluded
+}
+
+// Split removes the resources for which split returns true from rm and returns
+// them in a ResourceManager acting for the same window with the same settings.
+func (rm *ResourceManager) Split(split func(types.SnoozableResource) bool) *ResourceManager {
+	other := &ResourceManager{
+		resources:   rm.Exclude(split),
+		Owner:       rm.Owner,
+		Tiers:       rm.Tiers,
+		WakeLimiter: rm.WakeLimiter,
+		Notifier:    rm.Notifier,
+		DryRun:      rm.DryRun,
+	}
+	if other.resources == nil {
+		other.resources = make([]types.SnoozableResource, 0)
+	}
+	return other
+}
+
+// Woken returns the resources rm woke, in the order it woke them.
+func (rm *ResourceManager) Woken() []types.SnoozableResource {
+	return rm.woken
+}
+
+// Owned returns the snoozed resources that rm's window snoozed.
+func (rm *ResourceManager) Owned() []types.SnoozableResource {
+	var owned []types.SnoozableResource
+	for _, resource := range rm.resources {
+		if rm.owns(resource) {
+			owned = append(owned, resource)
+		}
+	}
+	return owned
+}
+
+// owns reports whether resource is snoozed by rm's window. A resource snoozed
+// before ownership was recorded is owned when it carries a window's backup,
+// so a Job or CronJob suspended by someone else is left alone.
+func (rm *ResourceManager) owns(resource types.SnoozableResource) bool {
+	if !resource.IsSnoozed() {
+		return false
+	}
+	annotations := resource.GetAnnotations()
+	if owner, exists := annotations[schedulingv1alpha1.SnoozedByAnnotation]; exists {
+		return owner == rm.Owner
+	}
+	_, replicas := annotations[workloads.BackupReplicasKey]
+	_, selector := annotations[service.BackupSelectorKey]
+	return replicas || selector
+}
+
+// DryRunChanges returns the changes validated in dry-run mode, in the order
+// they were made.
+func (rm *ResourceManager) DryRunChanges() []schedulingv1alpha1.DryRunChange {
+	return rm.dryRunChanges
+}
+
+// SnoozedRequests returns the resources the snoozed replicas of every owned
+// resource would request if they were running.
+func (rm *ResourceManager) SnoozedRequests() corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, resource := range rm.Owned() {
+		for name, quantity := range resource.SnoozedRequests() {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	return requests
+}
+
+// WakeOverrides returns the resources that their own wake override annotation
+// holds awake at now.
+func (rm *ResourceManager) WakeOverrides(now time.Time) []types.SnoozableResource {
+	var overridden []types.SnoozableResource
+	for _, resource := range rm.resources {
+		if _, wakeOverride, _ := utils.WakeOverrideUntil(resource.GetAnnotations(), now); wakeOverride {
+			overridden = append(overridden, resource)
+		}
+	}
+	return overridden
+}
+
+// SnoozeAll snoozes every resource of rm at once, in the reverse of the wake
+// order.
 func (rm *ResourceManager) SnoozeAll(ctx context.Context, r client.Client) error {
+	resources := rm.ordered()
+	slices.Reverse(resources)
+	snoozed := len(rm.snoozed)
+	err := rm.snoozeResources(ctx, r, resources)
+	return rm.finish(ctx, schedulingv1alpha1.ActionSnooze, len(rm.snoozed) > snoozed, err)
+}
+
+// WakeAll wakes every resource of rm at once, in wake order.
+func (rm *ResourceManager) WakeAll(ctx context.Context, r client.Client) error {
+	woken := len(rm.woken)
+	err := rm.wakeResources(ctx, r, rm.ordered(), false)
+	return rm.finish(ctx, schedulingv1alpha1.ActionWake, len(rm.woken) > woken, err)
+}
+
+// SnoozeTiers snoozes the tiers of rm from the last to the first. It stops
+// after a tier whose resources are still scaling down and returns it, so the
+// caller can come back for the tiers before it. It returns nil once every
+// tier has been snoozed. In dry-run mode nothing scales down, so every tier
+// is handled at once.
+func (rm *ResourceManager) SnoozeTiers(ctx context.Context, r client.Client) (*Tier, error) {
+	tiers := rm.Sequence()
+	snoozed := len(rm.snoozed)
+	for i := len(tiers) - 1; i >= 0; i-- {
+		if err := rm.snoozeResources(ctx, r, tiers[i].Resources); err != nil {
+			return nil, rm.finish(ctx, schedulingv1alpha1.ActionSnooze, false, err)
+		}
+		if i > 0 && !rm.DryRun && len(tiers[i].Pending(true)) > 0 {
+			return &tiers[i], nil
+		}
+	}
+	return nil, rm.finish(ctx, schedulingv1alpha1.ActionSnooze, len(rm.snoozed) > snoozed, nil)
+}
+
+// WakeTiers wakes the tiers of rm from the first to the last. It stops after
+// a tier whose resources are not ready yet and returns it, so the caller can
+// come back for the tiers after it. It returns nil once every tier has been
+// woken, or when rm.WakeLimiter held a resource back, which ThrottledFor then
+// reports. Once rm.WakeDeadline has passed it no longer stops at a tier, and
+// OverdueTiers reports the tiers it did not wait for.
+func (rm *ResourceManager) WakeTiers(ctx context.Context, r client.Client) (*Tier, error) {
+	tiers := rm.Sequence()
+	woken := len(rm.woken)
+	rm.overdue = nil
+	for i := range tiers {
+		if err := rm.wakeResources(ctx, r, tiers[i].Resources, true); err != nil {
+			return nil, rm.finish(ctx, schedulingv1alpha1.ActionWake, false, err)
+		}
+		if rm.throttledFor > 0 {
+			return nil, nil
+		}
+		if i < len(tiers)-1 && !rm.DryRun && len(tiers[i].Pending(false)) > 0 {
+			if rm.WakeDeadline.IsZero() || time.Now().Before(rm.WakeDeadline) {
+				return &tiers[i], nil
+			}
+			logf.FromContext(ctx).Info("Tier not ready by the wake deadline, waking the next tier", "tier", tiers[i].Name)
+			rm.overdue = append(rm.overdue, tiers[i].Name)
+		}
+	}
+	return nil, rm.finish(ctx, schedulingv1alpha1.ActionWake, len(rm.woken) > woken, nil)
+}
+
+// OverdueTiers returns the names of the tiers the last call of WakeTiers moved
+// past because they were not ready by rm.WakeDeadline.
+func (rm *ResourceManager) OverdueTiers() []string {
+	return rm.overdue
+}
+
+// wokenByWake reports whether the wake in progress scaled resource up.
+func (rm *ResourceManager) wokenByWake(resource types.SnoozableResource) bool {
+	return rm.WokenByWake == nil || rm.WokenByWake(resource) || slices.Contains(rm.woken, resource)
+}
+
+// ThrottledFor returns how long until rm.WakeLimiter lets the last call of
+// WakeTiers carry on, or zero when it held nothing back.
+func (rm *ResourceManager) ThrottledFor() time.Duration {
+	return rm.throttledFor
+}
+
+// finish tells rm.Notifier about a snooze or wake that failed with err, or
+// that finished having changed a resource, and returns err. A finished snooze
+// is reported with the resources the window holds asleep, a finished wake with
+// the resources that are awake, so a transition spread over several tiers is
+// reported whole.
+func (rm *ResourceManager) finish(ctx context.Context, action string, changed bool, err error) error {
+	switch {
+	case rm.Notifier == nil || rm.DryRun:
+	case err != nil:
+		rm.Notifier.Notify(ctx, action, nil, err)
+	case changed && action == schedulingv1alpha1.ActionSnooze:
+		rm.Notifier.Notify(ctx, action, rm.Owned(), nil)
+	case changed:
+		rm.Notifier.Notify(ctx, action, slices.DeleteFunc(slices.Clone(rm.resources), types.SnoozableResource.IsSnoozed), nil)
+	}
+	return err
+}
+
+// ordered returns rm's resources in wake order.
+func (rm *ResourceManager) ordered() []types.SnoozableResource {
+	var resources []types.SnoozableResource
+	for _, tier := range rm.Sequence() {
+		resources = append(resources, tier.Resources...)
+	}
+	return resources
+}
+
+func (rm *ResourceManager) snoozeResources(ctx context.Context, r client.Client, resources []types.SnoozableResource) error {
 	logger := logf.FromContext(ctx)
+	now := time.Now()
 
-	for _, resource := range rm.resources {
-		if resource.IsSnoozed() {
+	for _, resource := range resources {
+		if _, wakeOverride, _ := utils.WakeOverrideUntil(resource.GetAnnotations(), now); wakeOverride {
+			if !resource.IsSnoozed() {
+				continue
+			}
+
+			logger.Info("Waking resource for wake override",
+				"type", resource.GetResourceType(),
+				"name", resource.GetName())
+
+			if err := rm.wake(ctx, r, resource); err != nil {
+				logger.Error(err, "Failed to wake resource",
+					"type", resource.GetResourceType(),
+					"name", resource.GetName())
+				return err
+			}
+			continue
+		}
+
+		// A resource snoozed by another window is taken over by snoozing it again
+		if resource.IsSnoozed() && !resource.IsDrifted() && resource.GetAnnotations()[schedulingv1alpha1.SnoozedByAnnotation] == rm.Owner {
 			logger.Info("Resource already snoozed, skipping",
 				"type", resource.GetResourceType(),
 				"name", resource.GetName())
 			continue
 		}
 
+		if _, overridden := resource.GetAnnotations()[workloads.OverrideKey]; overridden {
+			logger.Info("Resource scaled up by override, skipping",
+				"type", resource.GetResourceType(),
+				"name", resource.GetName())
+			continue
+		}
+
 		logger.Info("Snoozing resource",
 			"type", resource.GetResourceType(),
-			"name", resource.GetName())
+			"name", resource.GetName(),
+			"drifted", resource.IsDrifted())
 
-		if err := resource.Snooze(ctx, r); err != nil {
+		if err := rm.snooze(ctx, r, resource); err != nil {
 			logger.Error(err, "Failed to snooze resource",
 				"type", resource.GetResourceType(),
 				"name", resource.GetName())
@@ -48,19 +340,32 @@ func (rm *ResourceManager) SnoozeAll(ctx context.Context, r client.Client) error
 	return nil
 }
 
-func (rm *ResourceManager) WakeAll(ctx context.Context, r client.Client) error {
+// wakeResources wakes the resources among resources that rm's window snoozed.
+// When limited, it stops at the first one rm.WakeLimiter holds back.
+func (rm *ResourceManager) wakeResources(ctx context.Context, r client.Client, resources []types.SnoozableResource, limited bool) error {
 	logger := logf.FromContext(ctx)
 
-	for _, resource := range rm.resources {
-		if !resource.IsSnoozed() {
+	for _, resource := range resources {
+		if !rm.owns(resource) {
 			continue
 		}
 
+		if limited && rm.WakeLimiter != nil && !rm.DryRun {
+			if delay := rm.WakeLimiter.Reserve(time.Now()); delay > 0 {
+				logger.Info("Wake rate reached, holding resource back",
+					"type", resource.GetResourceType(),
+					"name", resource.GetName(),
+					"delay", delay)
+				rm.throttledFor = delay
+				return nil
+			}
+		}
+
 		logger.Info("Waking resource",
 			"type", resource.GetResourceType(),
 			"name", resource.GetName())
 
-		if err := resource.Wake(ctx, r); err != nil {
+		if err := rm.wake(ctx, r, resource); err != nil {
 			logger.Error(err, "Failed to wake resource",
 				"type", resource.GetResourceType(),
 				"name", resource.GetName())
@@ -70,3 +375,60 @@ func (rm *ResourceManager) WakeAll(ctx context.Context, r client.Client) error {
 
 	return nil
 }
+
+// SnoozeResource snoozes a single resource on behalf of the window named owner, or
+// hands an already snoozed resource over to it.
+func (rm *ResourceManager) SnoozeResource(ctx context.Context, r client.Client, resource types.SnoozableResource, owner string) error {
+	return rm.snoozeAs(ctx, r, resource, owner)
+}
+
+// WakeResource wakes a single resource, whichever window snoozed it.
+func (rm *ResourceManager) WakeResource(ctx context.Context, r client.Client, resource types.SnoozableResource) error {
+	return rm.wake(ctx, r, resource)
+}
+
+// snooze snoozes a single resource for rm's window.
+func (rm *ResourceManager) snooze(ctx context.Context, r client.Client, resource types.SnoozableResource) error {
+	return rm.snoozeAs(ctx, r, resource, rm.Owner)
+}
+
+// snoozeAs snoozes a single resource for owner and records the operation in
+// the metrics.
+func (rm *ResourceManager) snoozeAs(ctx context.Context, r client.Client, resource types.SnoozableResource, owner string) error {
+	if rm.DryRun {
+		rm.recordDryRun(schedulingv1alpha1.ActionSnooze, resource)
+		return resource.Snooze(ctx, client.NewDryRunClient(r), owner)
+	}
+
+	start := time.Now()
+	err := resource.Snooze(ctx, r, owner)
+	metrics.ObserveOperation(metrics.OperationSnooze, resource, start, err)
+	if err == nil {
+		rm.snoozed = append(rm.snoozed, resource)
+	}
+	return err
+}
+
+// wake wakes a single resource and records the operation in the metrics.
+func (rm *ResourceManager) wake(ctx context.Context, r client.Client, resource types.SnoozableResource) error {
+	if rm.DryRun {
+		rm.recordDryRun(schedulingv1alpha1.ActionWake, resource)
+		return resource.Wake(ctx, client.NewDryRunClient(r))
+	}
+
+	start := time.Now()
+	err := resource.Wake(ctx, r)
+	metrics.ObserveOperation(metrics.OperationWake, resource, start, err)
+	if err == nil {
+		rm.woken = append(rm.woken, resource)
+	}
+	return err
+}
+
+func (rm *ResourceManager) recordDryRun(action string, resource types.SnoozableResource) {
+	rm.dryRunChanges = append(rm.dryRunChanges, schedulingv1alpha1.DryRunChange{
+		Action: action,
+		Kind:   resource.GetResourceType(),
+		Name:   resource.GetName(),
+	})
+}
@@ -0,0 +1,145 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/jobs"
+	"codeacme.org/kube-snooze/internal/controller/adapter/service"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+// ForWindow returns a ResourceManager for the resources snoozeWindow selects:
+// those of its resource types in its namespace that match its label selector.
+func ForWindow(ctx context.Context, c client.Reader, snoozeWindow *schedulingv1alpha1.SnoozeWindow) (*ResourceManager, error) {
+	resourceManager := NewResourceManager()
+	resourceManager.Owner = snoozeWindow.Name
+	resourceManager.Tiers = snoozeWindow.Spec.Tiers
+	listOpts := []client.ListOption{
+		client.InNamespace(snoozeWindow.Namespace),
+		client.MatchingLabels(snoozeWindow.Spec.LabelSelector),
+	}
+
+	// Fetching Workloads
+	if ManagesResourceType(snoozeWindow, schedulingv1alpha1.ResourceTypeDeployment) {
+		var deploymentsList appsv1.DeploymentList
+		if err := c.List(ctx, &deploymentsList, listOpts...); err != nil {
+			return nil, err
+		}
+		for _, deploy := range deploymentsList.Items {
+			resourceManager.AddResource(workloads.NewDeploymentAdapter(&deploy))
+		}
+	}
+
+	if ManagesResourceType(snoozeWindow, schedulingv1alpha1.ResourceTypeStatefulSet) {
+		var statefulsetsList appsv1.StatefulSetList
+		if err := c.List(ctx, &statefulsetsList, listOpts...); err != nil {
+			return nil, err
+		}
+		for _, statefulset := range statefulsetsList.Items {
+			resourceManager.AddResource(workloads.NewStatefulSetAdapter(&statefulset))
+		}
+	}
+
+	// Fetching Jobs
+	if ManagesResourceType(snoozeWindow, schedulingv1alpha1.ResourceTypeJob) {
+		var jobsList batchv1.JobList
+		if err := c.List(ctx, &jobsList, listOpts...); err != nil {
+			return nil, err
+		}
+		for _, job := range jobsList.Items {
+			// Hook Jobs are run by windows, not snoozed by them.
+			if _, isHook := job.Labels[schedulingv1alpha1.HookLabel]; isHook {
+				continue
+			}
+			resourceManager.AddResource(jobs.NewJobAdapter(&job))
+		}
+	}
+
+	if ManagesResourceType(snoozeWindow, schedulingv1alpha1.ResourceTypeCronJob) {
+		var cronjobsList batchv1.CronJobList
+		if err := c.List(ctx, &cronjobsList, listOpts...); err != nil {
+			return nil, err
+		}
+		// The CronJobs the window's hooks take their job template from are run
+		// by the window, not snoozed by it.
+		templates := make(map[string]bool)
+		for _, hook := range slices.Concat(snoozeWindow.Spec.PreSnoozeHooks, snoozeWindow.Spec.PostWakeHooks) {
+			templates[hook.JobTemplateRef.Name] = true
+		}
+		for _, cronjob := range cronjobsList.Items {
+			if templates[cronjob.Name] {
+				continue
+			}
+			resourceManager.AddResource(jobs.NewCronJobAdapter(&cronjob))
+		}
+	}
+
+	// Fetching Services, which only windows routing them through the activator manage
+	if snoozeWindow.Spec.Activator != nil && ManagesResourceType(snoozeWindow, schedulingv1alpha1.ResourceTypeService) {
+		activator, err := activatorEndpoints(ctx, c, snoozeWindow.Spec.Activator.Service)
+		if err != nil {
+			return nil, err
+		}
+		var servicesList corev1.ServiceList
+		if err := c.List(ctx, &servicesList, listOpts...); err != nil {
+			return nil, err
+		}
+		for _, svc := range servicesList.Items {
+			// Backend Services stand in for snoozed ones, and Services without a
+			// selector or cluster IP cannot be pointed at the activator
+			if _, isBackend := svc.Labels[service.BackendOfLabel]; isBackend || svc.Spec.ClusterIP == corev1.ClusterIPNone {
+				continue
+			}
+			if _, snoozed := svc.Annotations[service.BackupSelectorKey]; !snoozed && len(svc.Spec.Selector) == 0 {
+				continue
+			}
+			resourceManager.AddResource(service.NewServiceAdapter(&svc, activator))
+		}
+	}
+
+	return resourceManager, nil
+}
+
+// activatorEndpoints returns the ready endpoints of the activator Service
+// named by reference, as namespace/name.
+func activatorEndpoints(ctx context.Context, c client.Reader, reference string) (*service.Activator, error) {
+	namespace, name, found := strings.Cut(reference, "/")
+	if !found {
+		return nil, fmt.Errorf("invalid activator service %q, expected namespace/name", reference)
+	}
+
+	var slices discoveryv1.EndpointSliceList
+	if err := c.List(ctx, &slices, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: name}); err != nil {
+		return nil, err
+	}
+	activator := &service.Activator{}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				activator.Addresses = append(activator.Addresses, endpoint.Addresses...)
+			}
+		}
+	}
+	return activator, nil
+}
+
+// ManagesResourceType reports whether the window's resourceTypes include the
+// given type. A window without resourceTypes manages every supported type.
+func ManagesResourceType(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceType string) bool {
+	if len(snoozeWindow.Spec.ResourceTypes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(snoozeWindow.Spec.ResourceTypes, func(t string) bool {
+		return strings.EqualFold(t, resourceType)
+	})
+}
@@ -0,0 +1,162 @@
+package adapter
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+// Tier is a group of resources that are snoozed and woken together, once the
+// tiers before it in the sequence have settled.
+type Tier struct {
+	Name      string
+	Resources []types.SnoozableResource
+
+	wokenByWake func(types.SnoozableResource) bool
+}
+
+// Pending returns the resources of the tier that have not settled yet. With
+// snoozed set these are the snoozed resources still running replicas, leaving
+// out those scaled back up by an override; otherwise the resources the wake in
+// progress scaled up that are not ready yet.
+func (t Tier) Pending(snoozed bool) []types.SnoozableResource {
+	var pending []types.SnoozableResource
+	for _, resource := range t.Resources {
+		if resource.IsSnoozed() != snoozed || resource.IsSettled() {
+			continue
+		}
+		if snoozed && resource.IsDrifted() {
+			continue
+		}
+		if !snoozed && t.wokenByWake != nil && !t.wokenByWake(resource) {
+			continue
+		}
+		pending = append(pending, resource)
+	}
+	return pending
+}
+
+// typeOrder is the order resources of a tier are woken in. Databases usually
+// run as StatefulSets, so they come up before the workloads using them.
+// Services come last, so they are pointed at the activator before their
+// workloads are snoozed.
+var typeOrder = []string{
+	schedulingv1alpha1.ResourceTypeStatefulSet,
+	schedulingv1alpha1.ResourceTypeDeployment,
+	"replicaset",
+	schedulingv1alpha1.ResourceTypeCronJob,
+	schedulingv1alpha1.ResourceTypeJob,
+	schedulingv1alpha1.ResourceTypeService,
+}
+
+// Sequence groups rm's resources into tiers in wake order. A resource belongs
+// to the first of rm.Tiers that selects it, or to the tier named by its tier
+// annotation, and resources no tier selects come after the declared ones. A
+// resource is then moved past the tiers of the resources listed in its
+// depends-on annotation; a dependency cycle is broken after as many passes as
+// there are resources. Within a tier, StatefulSets come first.
+func (rm *ResourceManager) Sequence() []Tier {
+	ranks := make(map[string]int, len(rm.resources))
+	for _, resource := range rm.resources {
+		ranks[resourceKey(resource)] = rm.tierOf(resource)
+	}
+
+	for range rm.resources {
+		moved := false
+		for _, resource := range rm.resources {
+			key := resourceKey(resource)
+			for _, dependency := range dependsOn(resource) {
+				if rank, exists := ranks[dependency]; exists && ranks[key] <= rank {
+					ranks[key] = rank + 1
+					moved = true
+				}
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	byRank := make(map[int][]types.SnoozableResource)
+	for _, resource := range rm.resources {
+		rank := ranks[resourceKey(resource)]
+		byRank[rank] = append(byRank[rank], resource)
+	}
+
+	var tiers []Tier
+	for _, rank := range slices.Sorted(maps.Keys(byRank)) {
+		resources := byRank[rank]
+		slices.SortStableFunc(resources, func(a, b types.SnoozableResource) int {
+			return cmp.Compare(typeRank(a), typeRank(b))
+		})
+		name := strconv.Itoa(rank)
+		if rank < len(rm.Tiers) {
+			name = rm.Tiers[rank].Name
+		}
+		tiers = append(tiers, Tier{Name: name, Resources: resources, wokenByWake: rm.wokenByWake})
+	}
+	return tiers
+}
+
+// tierOf returns the index of the tier resource belongs to before its
+// dependencies are taken into account.
+func (rm *ResourceManager) tierOf(resource types.SnoozableResource) int {
+	if value, exists := resource.GetAnnotations()[schedulingv1alpha1.TierAnnotation]; exists {
+		if tier, err := strconv.Atoi(value); err == nil && tier >= 0 {
+			return tier
+		}
+	}
+
+	for i, tier := range rm.Tiers {
+		if tierSelects(tier, resource) {
+			return i
+		}
+	}
+	return len(rm.Tiers)
+}
+
+func tierSelects(tier schedulingv1alpha1.SnoozeTier, resource types.SnoozableResource) bool {
+	labels := resource.GetLabels()
+	for key, value := range tier.LabelSelector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return len(tier.ResourceTypes) == 0 || slices.ContainsFunc(tier.ResourceTypes, func(t string) bool {
+		return strings.EqualFold(t, resource.GetResourceType())
+	})
+}
+
+// dependsOn returns the keys of the resources listed in the depends-on
+// annotation of resource. A bare name refers to a deployment.
+func dependsOn(resource types.SnoozableResource) []string {
+	value := resource.GetAnnotations()[schedulingv1alpha1.DependsOnAnnotation]
+	var keys []string
+	for _, dependency := range strings.Split(value, ",") {
+		dependency = strings.ToLower(strings.TrimSpace(dependency))
+		if dependency == "" {
+			continue
+		}
+		if !strings.Contains(dependency, "/") {
+			dependency = schedulingv1alpha1.ResourceTypeDeployment + "/" + dependency
+		}
+		keys = append(keys, dependency)
+	}
+	return keys
+}
+
+func resourceKey(resource types.SnoozableResource) string {
+	return resource.GetResourceType() + "/" + resource.GetName()
+}
+
+func typeRank(resource types.SnoozableResource) int {
+	if rank := slices.Index(typeOrder, resource.GetResourceType()); rank >= 0 {
+		return rank
+	}
+	return len(typeOrder)
+}
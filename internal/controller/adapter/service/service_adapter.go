@@ -2,17 +2,91 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+const (
+	// BackupSelectorKey holds the selector of a snoozed Service, as JSON, while
+	// the Service points at the activator.
+	BackupSelectorKey = "kube-snooze/selector"
+	// ActivatorEndpointsKey records the activator endpoints a snoozed Service
+	// was pointed at, so it is pointed again when they change.
+	ActivatorEndpointsKey = "kube-snooze/activator-endpoints"
+	// BackendOfLabel names the Service a backend Service stands in for.
+	BackendOfLabel = "kube-snooze/backend-of"
+
+	// ManagedBy is the endpointslice.kubernetes.io/managed-by value of the
+	// EndpointSlices pointing snoozed Services at the activator.
+	ManagedBy = "kube-snooze"
+
+	// FirstActivatorPort and LastActivatorPort bound the ports the ports of
+	// snoozed Services are pointed at on the activator. Each Service port gets
+	// its own, so the activator tells where a request was sent by the port it
+	// came in on.
+	FirstActivatorPort int32 = 20000
+	LastActivatorPort  int32 = 29999
+
+	backendSuffix = "-kube-snooze"
 )
 
+// Activator is where the snoozed Services of a window are pointed at: the
+// ready addresses of the activator's pods.
+type Activator struct {
+	Addresses []string
+}
+
+// key identifies the endpoints of a, to tell when they changed.
+func (a *Activator) key() string {
+	return strings.Join(slices.Sorted(slices.Values(a.Addresses)), ",")
+}
+
+// ServiceAdapter snoozes a Service by pointing it at the activator, which
+// wakes the workloads behind the Service on the first request. The pods the
+// Service selected stay reachable through its backend Service, which the
+// activator forwards requests to once they are available.
 type ServiceAdapter struct {
-	service *corev1.Service
+	service   *corev1.Service
+	activator *Activator
 }
 
-func NewServiceAdapter(service *corev1.Service) *ServiceAdapter {
-	return &ServiceAdapter{service: service}
+func NewServiceAdapter(service *corev1.Service, activator *Activator) *ServiceAdapter {
+	return &ServiceAdapter{service: service, activator: activator}
+}
+
+// BackendName returns the name of the backend Service of the Service name.
+func BackendName(name string) string {
+	if limit := 63 - len(backendSuffix); len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-")
+	}
+	return name + backendSuffix
+}
+
+// BackupSelector returns the selector of a snoozed Service, and false when
+// the Service is not snoozed.
+func BackupSelector(service *corev1.Service) (map[string]string, bool, error) {
+	value, exists := service.Annotations[BackupSelectorKey]
+	if !exists {
+		return nil, false, nil
+	}
+	var selector map[string]string
+	if err := json.Unmarshal([]byte(value), &selector); err != nil {
+		return nil, true, err
+	}
+	return selector, true, nil
 }
 
 func (s *ServiceAdapter) GetName() string {
@@ -23,6 +97,10 @@ func (s *ServiceAdapter) GetNamespace() string {
 	return s.service.Namespace
 }
 
+func (s *ServiceAdapter) GetLabels() map[string]string {
+	return s.service.Labels
+}
+
 func (s *ServiceAdapter) GetAnnotations() map[string]string {
 	return s.service.Annotations
 }
@@ -32,18 +110,262 @@ func (s *ServiceAdapter) SetAnnotations(annotations map[string]string) {
 }
 
 func (s *ServiceAdapter) IsSnoozed() bool {
-	// Enter the Service Snoze logic here
-	return false
+	_, isSnoozed := s.service.Annotations[BackupSelectorKey]
+	return isSnoozed
 }
 
-func (s *ServiceAdapter) Snooze(ctx context.Context, r client.Client) error {
-	return nil
+// IsDrifted reports whether a snoozed Service got a selector back outside of
+// kube-snooze, or points at activator endpoints that changed since.
+func (s *ServiceAdapter) IsDrifted() bool {
+	if !s.IsSnoozed() {
+		return false
+	}
+	if len(s.service.Spec.Selector) > 0 {
+		return true
+	}
+	return s.activator != nil && len(s.activator.Addresses) > 0 &&
+		s.service.Annotations[ActivatorEndpointsKey] != s.activator.key()
+}
+
+// IsSettled returns true, a Service points at its new endpoints right away.
+func (s *ServiceAdapter) IsSettled() bool {
+	return true
+}
+
+// Snooze points the Service at the activator. It fails when the activator has
+// no ready endpoints, as the Service would then drop every request.
+func (s *ServiceAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	if s.activator == nil || len(s.activator.Addresses) == 0 {
+		return errors.New("the activator has no ready endpoints")
+	}
+
+	selector := s.service.Spec.Selector
+	if backup, snoozed, err := BackupSelector(s.service); err != nil {
+		return err
+	} else if snoozed {
+		selector = backup
+	}
+	if err := s.ensureBackend(ctx, r, selector); err != nil {
+		return err
+	}
+
+	backup, err := json.Marshal(selector)
+	if err != nil {
+		return err
+	}
+	if err := utils.PatchWithRetry(ctx, r, s.service, func() error {
+		metav1.SetMetaDataAnnotation(&s.service.ObjectMeta, BackupSelectorKey, string(backup))
+		metav1.SetMetaDataAnnotation(&s.service.ObjectMeta, ActivatorEndpointsKey, s.activator.key())
+		metav1.SetMetaDataAnnotation(&s.service.ObjectMeta, schedulingv1alpha1.SnoozedByAnnotation, owner)
+		s.service.Spec.Selector = nil
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// The endpoint slice controller leaves the slices of a Service without a
+	// selector alone, so the ones it made are removed here
+	if err := r.DeleteAllOf(ctx, &discoveryv1.EndpointSlice{}, client.InNamespace(s.service.Namespace), client.MatchingLabels{
+		discoveryv1.LabelServiceName: s.service.Name,
+		discoveryv1.LabelManagedBy:   "endpointslice-controller.k8s.io",
+	}); err != nil {
+		return err
+	}
+	return s.ensureEndpointSlice(ctx, r)
 }
 
+// Wake gives the Service its selector back and removes its backend Service
+// and the EndpointSlice pointing at the activator.
 func (s *ServiceAdapter) Wake(ctx context.Context, r client.Client) error {
+	selector, snoozed, err := BackupSelector(s.service)
+	if err != nil || !snoozed {
+		return err
+	}
+
+	if err := utils.PatchWithRetry(ctx, r, s.service, func() error {
+		s.service.Spec.Selector = selector
+		delete(s.service.Annotations, BackupSelectorKey)
+		delete(s.service.Annotations, ActivatorEndpointsKey)
+		delete(s.service.Annotations, schedulingv1alpha1.SnoozedByAnnotation)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	slice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: s.service.Name + backendSuffix, Namespace: s.service.Namespace}}
+	if err := r.Delete(ctx, slice); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	backend := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: BackendName(s.service.Name), Namespace: s.service.Namespace}}
+	return client.IgnoreNotFound(r.Delete(ctx, backend))
+}
+
+// ensureBackend creates the backend Service, which selects the pods the
+// Service selected while it points at the activator, or brings an existing
+// one in line with the Service.
+func (s *ServiceAdapter) ensureBackend(ctx context.Context, r client.Client, selector map[string]string) error {
+	backend := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackendName(s.service.Name),
+			Namespace: s.service.Namespace,
+		},
+	}
+	mutate := func() error {
+		metav1.SetMetaDataLabel(&backend.ObjectMeta, BackendOfLabel, s.service.Name)
+		backend.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       s.service.Name,
+			UID:        s.service.UID,
+		}}
+		backend.Spec.Selector = selector
+		backend.Spec.Ports = make([]corev1.ServicePort, 0, len(s.service.Spec.Ports))
+		for _, port := range s.service.Spec.Ports {
+			port.NodePort = 0
+			backend.Spec.Ports = append(backend.Spec.Ports, port)
+		}
+		return nil
+	}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(backend), backend); apierrors.IsNotFound(err) {
+		if err := mutate(); err != nil {
+			return err
+		}
+		return r.Create(ctx, backend)
+	} else if err != nil {
+		return err
+	}
+	return utils.PatchWithRetry(ctx, r, backend, mutate)
+}
+
+// ensureEndpointSlice points the Service at the activator's endpoints, each
+// of its ports at an activator port of its own.
+func (s *ServiceAdapter) ensureEndpointSlice(ctx context.Context, r client.Client) error {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.service.Name + backendSuffix,
+			Namespace: s.service.Namespace,
+		},
+	}
+	addressType := discoveryv1.AddressTypeIPv4
+	if len(s.service.Spec.IPFamilies) > 0 && s.service.Spec.IPFamilies[0] == corev1.IPv6Protocol {
+		addressType = discoveryv1.AddressTypeIPv6
+	}
+	mutate := func() error {
+		ports, err := s.activatorPorts(ctx, r, slice)
+		if err != nil {
+			return err
+		}
+
+		slice.Labels = map[string]string{
+			discoveryv1.LabelServiceName: s.service.Name,
+			discoveryv1.LabelManagedBy:   ManagedBy,
+		}
+		slice.AddressType = addressType
+		slice.Endpoints = make([]discoveryv1.Endpoint, 0, len(s.activator.Addresses))
+		for _, address := range s.activator.Addresses {
+			slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{address},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+			})
+		}
+		slice.Ports = make([]discoveryv1.EndpointPort, 0, len(s.service.Spec.Ports))
+		for _, port := range s.service.Spec.Ports {
+			slice.Ports = append(slice.Ports, discoveryv1.EndpointPort{
+				Name:     ptr.To(port.Name),
+				Port:     ptr.To(ports[port.Name]),
+				Protocol: ptr.To(port.Protocol),
+			})
+		}
+		return nil
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(slice), slice)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && slice.AddressType == addressType {
+		return utils.PatchWithRetry(ctx, r, slice, mutate)
+	}
+	// The address type of a slice cannot change, so a slice of the other
+	// family is replaced
+	if err == nil {
+		if err := r.Delete(ctx, slice); err != nil {
+			return err
+		}
+		slice = &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: slice.Name, Namespace: slice.Namespace}}
+	}
+	if err := mutate(); err != nil {
+		return err
+	}
+	return r.Create(ctx, slice)
+}
+
+// activatorPorts returns the activator port of each port of the Service by
+// name. The ports current already points at are kept, and new ones are picked
+// from a hash of the Service port, skipping those other snoozed Services use.
+func (s *ServiceAdapter) activatorPorts(ctx context.Context, r client.Client, current *discoveryv1.EndpointSlice) (map[string]int32, error) {
+	var managed discoveryv1.EndpointSliceList
+	if err := r.List(ctx, &managed, client.MatchingLabels{discoveryv1.LabelManagedBy: ManagedBy}); err != nil {
+		return nil, err
+	}
+	used := make(map[int32]bool)
+	for _, slice := range managed.Items {
+		if slice.Namespace == current.Namespace && slice.Name == current.Name {
+			continue
+		}
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				used[*port.Port] = true
+			}
+		}
+	}
+
+	ports := make(map[string]int32, len(s.service.Spec.Ports))
+	for _, port := range current.Ports {
+		if port.Name != nil && port.Port != nil && inActivatorRange(*port.Port) && !used[*port.Port] {
+			ports[*port.Name] = *port.Port
+			used[*port.Port] = true
+		}
+	}
+	span := uint32(LastActivatorPort - FirstActivatorPort + 1)
+	for _, port := range s.service.Spec.Ports {
+		if _, kept := ports[port.Name]; kept {
+			continue
+		}
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(s.service.Namespace + "/" + s.service.Name + "/" + port.Name))
+		start := hash.Sum32() % span
+		for offset := range span {
+			candidate := FirstActivatorPort + int32((start+offset)%span)
+			if !used[candidate] {
+				ports[port.Name] = candidate
+				used[candidate] = true
+				break
+			}
+		}
+		if _, found := ports[port.Name]; !found {
+			return nil, errors.New("no activator port is left for the service")
+		}
+	}
+	return ports, nil
+}
+
+// inActivatorRange reports whether port is an activator port.
+func inActivatorRange(port int32) bool {
+	return port >= FirstActivatorPort && port <= LastActivatorPort
+}
+
+// Annotate sets the annotation key of the service to value, or removes it when
+// value is empty.
+func (s *ServiceAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, s.service, key, value)
+}
+
+func (s *ServiceAdapter) SnoozedRequests() corev1.ResourceList {
 	return nil
 }
 
 func (s *ServiceAdapter) GetResourceType() string {
-	return "service"
+	return schedulingv1alpha1.ResourceTypeService
 }
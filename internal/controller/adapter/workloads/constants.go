@@ -2,4 +2,10 @@ package workloads
 
 const (
 	BackupReplicasKey = "kube-snooze/replicas"
+	// OverrideKey marks a snoozed workload that was deliberately scaled back up
+	// while its window was active. It is left running until the window ends.
+	OverrideKey = "kube-snooze/override"
+	// SnoozedLabel marks a workload carrying the BackupReplicasKey annotation,
+	// so admission webhooks can select snoozed workloads with an objectSelector.
+	SnoozedLabel = "kube-snooze/snoozed"
 )
@@ -5,8 +5,13 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 type DeploymentAdapter struct {
@@ -25,6 +30,10 @@ func (d *DeploymentAdapter) GetNamespace() string {
 	return d.deployment.Namespace
 }
 
+func (d *DeploymentAdapter) GetLabels() map[string]string {
+	return d.deployment.GetLabels()
+}
+
 func (d *DeploymentAdapter) GetAnnotations() map[string]string {
 	return d.deployment.GetAnnotations()
 }
@@ -39,36 +48,96 @@ func (d *DeploymentAdapter) IsSnoozed() bool {
 	return isSnoozed
 }
 
-func (d *DeploymentAdapter) Snooze(ctx context.Context, r client.Client) error {
-	replicas := strconv.Itoa(int(*d.deployment.Spec.Replicas))
-	d.deployment.Spec.Replicas = ptr.To[int32](0)
-	annotations := d.GetAnnotations()
-	if annotations == nil {
-		annotations = make(map[string]string)
+// IsDrifted reports whether a snoozed deployment was scaled back up outside of kube-snooze.
+func (d *DeploymentAdapter) IsDrifted() bool {
+	return d.IsSnoozed() && ptr.Deref(d.deployment.Spec.Replicas, 1) > 0
+}
+
+// IsSettled reports whether a snoozed deployment has no replicas left, or an awake
+// one has all of its replicas ready.
+func (d *DeploymentAdapter) IsSettled() bool {
+	if d.deployment.Status.ObservedGeneration < d.deployment.Generation {
+		return false
 	}
-	annotations[BackupReplicasKey] = replicas
-	d.SetAnnotations(annotations)
-	return r.Update(ctx, d.deployment)
+	if d.IsSnoozed() {
+		return d.deployment.Status.Replicas == 0
+	}
+	return d.deployment.Status.ReadyReplicas >= ptr.Deref(d.deployment.Spec.Replicas, 1)
+}
+
+// Availability returns the available replicas of the deployment and the replicas
+// it should run.
+func (d *DeploymentAdapter) Availability() (int32, int32) {
+	return d.deployment.Status.AvailableReplicas, ptr.Deref(d.deployment.Spec.Replicas, 1)
+}
+
+func (d *DeploymentAdapter) PodSelector() *metav1.LabelSelector {
+	return d.deployment.Spec.Selector
+}
+
+func (d *DeploymentAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	return utils.PatchWithRetry(ctx, r, d.deployment, func() error {
+		annotations := d.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		// Keep an existing backup so a retry never records the already zeroed count
+		if _, exists := annotations[BackupReplicasKey]; !exists {
+			annotations[BackupReplicasKey] = strconv.Itoa(int(ptr.Deref(d.deployment.Spec.Replicas, 1)))
+		}
+		annotations[schedulingv1alpha1.SnoozedByAnnotation] = owner
+		d.deployment.Spec.Replicas = ptr.To[int32](0)
+		d.SetAnnotations(annotations)
+		labels := d.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[SnoozedLabel] = "true"
+		d.deployment.SetLabels(labels)
+		return nil
+	})
 }
 
 func (d *DeploymentAdapter) Wake(ctx context.Context, r client.Client) error {
-	annotations := d.deployment.GetAnnotations()
-	if storedReplicas, exists := annotations[BackupReplicasKey]; exists {
+	return utils.PatchWithRetry(ctx, r, d.deployment, func() error {
+		annotations := d.GetAnnotations()
+		storedReplicas, exists := annotations[BackupReplicasKey]
+		if !exists {
+			return nil
+		}
+
 		desiredReplicas, err := strconv.ParseInt(storedReplicas, 10, 32)
 		if err != nil {
 			return err
 		}
 
-		if desiredReplicas > 0 {
+		// An overridden deployment is already running at the size it was scaled to
+		if _, overridden := annotations[OverrideKey]; !overridden && desiredReplicas > 0 {
 			d.deployment.Spec.Replicas = ptr.To(int32(desiredReplicas))
 		}
 
-		// Clean up annotation
+		// Clean up annotations and the snoozed label
 		delete(annotations, BackupReplicasKey)
+		delete(annotations, OverrideKey)
+		delete(annotations, schedulingv1alpha1.SnoozedByAnnotation)
 		d.SetAnnotations(annotations)
-	}
+		labels := d.GetLabels()
+		delete(labels, SnoozedLabel)
+		d.deployment.SetLabels(labels)
+		return nil
+	})
+}
+
+// SnoozedRequests returns the resources the replicas scaled down by the snooze
+// would request.
+func (d *DeploymentAdapter) SnoozedRequests() corev1.ResourceList {
+	return snoozedRequests(d.GetAnnotations(), &d.deployment.Spec.Template)
+}
 
-	return r.Update(ctx, d.deployment)
+// Annotate sets the annotation key of the deployment to value, or removes it when
+// value is empty.
+func (d *DeploymentAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, d.deployment, key, value)
 }
 
 func (d *DeploymentAdapter) GetResourceType() string {
@@ -5,8 +5,13 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 type ReplicaSetAdapter struct {
@@ -25,6 +30,10 @@ func (rs *ReplicaSetAdapter) GetNamespace() string {
 	return rs.replicaset.Namespace
 }
 
+func (rs *ReplicaSetAdapter) GetLabels() map[string]string {
+	return rs.replicaset.GetLabels()
+}
+
 func (rs *ReplicaSetAdapter) GetAnnotations() map[string]string {
 	return rs.replicaset.GetAnnotations()
 }
@@ -39,36 +48,96 @@ func (rs *ReplicaSetAdapter) IsSnoozed() bool {
 	return isSnoozed
 }
 
-func (rs *ReplicaSetAdapter) Snooze(ctx context.Context, r client.Client) error {
-	replicas := strconv.Itoa(int(*rs.replicaset.Spec.Replicas))
-	rs.replicaset.Spec.Replicas = ptr.To[int32](0)
-	annotations := rs.GetAnnotations()
-	if annotations == nil {
-		annotations = make(map[string]string)
+// IsDrifted reports whether a snoozed replicaset was scaled back up outside of kube-snooze.
+func (rs *ReplicaSetAdapter) IsDrifted() bool {
+	return rs.IsSnoozed() && ptr.Deref(rs.replicaset.Spec.Replicas, 1) > 0
+}
+
+// IsSettled reports whether a snoozed replicaset has no replicas left, or an awake
+// one has all of its replicas ready.
+func (rs *ReplicaSetAdapter) IsSettled() bool {
+	if rs.replicaset.Status.ObservedGeneration < rs.replicaset.Generation {
+		return false
 	}
-	annotations[BackupReplicasKey] = replicas
-	rs.SetAnnotations(annotations)
-	return r.Update(ctx, rs.replicaset)
+	if rs.IsSnoozed() {
+		return rs.replicaset.Status.Replicas == 0
+	}
+	return rs.replicaset.Status.ReadyReplicas >= ptr.Deref(rs.replicaset.Spec.Replicas, 1)
+}
+
+// Availability returns the available replicas of the replicaset and the replicas
+// it should run.
+func (rs *ReplicaSetAdapter) Availability() (int32, int32) {
+	return rs.replicaset.Status.AvailableReplicas, ptr.Deref(rs.replicaset.Spec.Replicas, 1)
+}
+
+func (rs *ReplicaSetAdapter) PodSelector() *metav1.LabelSelector {
+	return rs.replicaset.Spec.Selector
+}
+
+func (rs *ReplicaSetAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	return utils.PatchWithRetry(ctx, r, rs.replicaset, func() error {
+		annotations := rs.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		// Keep an existing backup so a retry never records the already zeroed count
+		if _, exists := annotations[BackupReplicasKey]; !exists {
+			annotations[BackupReplicasKey] = strconv.Itoa(int(ptr.Deref(rs.replicaset.Spec.Replicas, 1)))
+		}
+		annotations[schedulingv1alpha1.SnoozedByAnnotation] = owner
+		rs.replicaset.Spec.Replicas = ptr.To[int32](0)
+		rs.SetAnnotations(annotations)
+		labels := rs.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[SnoozedLabel] = "true"
+		rs.replicaset.SetLabels(labels)
+		return nil
+	})
 }
 
 func (rs *ReplicaSetAdapter) Wake(ctx context.Context, r client.Client) error {
-	annotations := rs.replicaset.GetAnnotations()
-	if storedReplicas, exists := annotations[BackupReplicasKey]; exists {
+	return utils.PatchWithRetry(ctx, r, rs.replicaset, func() error {
+		annotations := rs.GetAnnotations()
+		storedReplicas, exists := annotations[BackupReplicasKey]
+		if !exists {
+			return nil
+		}
+
 		desiredReplicas, err := strconv.ParseInt(storedReplicas, 10, 32)
 		if err != nil {
 			return err
 		}
 
-		if desiredReplicas > 0 {
+		// An overridden replicaset is already running at the size it was scaled to
+		if _, overridden := annotations[OverrideKey]; !overridden && desiredReplicas > 0 {
 			rs.replicaset.Spec.Replicas = ptr.To(int32(desiredReplicas))
 		}
 
-		// Clean up annotation
+		// Clean up annotations and the snoozed label
 		delete(annotations, BackupReplicasKey)
+		delete(annotations, OverrideKey)
+		delete(annotations, schedulingv1alpha1.SnoozedByAnnotation)
 		rs.SetAnnotations(annotations)
-	}
+		labels := rs.GetLabels()
+		delete(labels, SnoozedLabel)
+		rs.replicaset.SetLabels(labels)
+		return nil
+	})
+}
+
+// SnoozedRequests returns the resources the replicas scaled down by the snooze
+// would request.
+func (rs *ReplicaSetAdapter) SnoozedRequests() corev1.ResourceList {
+	return snoozedRequests(rs.GetAnnotations(), &rs.replicaset.Spec.Template)
+}
 
-	return r.Update(ctx, rs.replicaset)
+// Annotate sets the annotation key of the replicaset to value, or removes it when
+// value is empty.
+func (rs *ReplicaSetAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, rs.replicaset, key, value)
 }
 
 func (rs *ReplicaSetAdapter) GetResourceType() string {
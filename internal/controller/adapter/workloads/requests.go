@@ -0,0 +1,34 @@
+package workloads
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// snoozedRequests returns the resources the replicas recorded in the backup
+// annotation would request with the given pod template. A workload without a
+// backup, or one scaled back up by an override, holds nothing back.
+func snoozedRequests(annotations map[string]string, template *corev1.PodTemplateSpec) corev1.ResourceList {
+	if _, overridden := annotations[OverrideKey]; overridden {
+		return nil
+	}
+	replicas, err := strconv.ParseInt(annotations[BackupReplicasKey], 10, 64)
+	if err != nil || replicas <= 0 {
+		return nil
+	}
+
+	requests := corev1.ResourceList{}
+	for _, container := range template.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for name, quantity := range requests {
+		quantity.Mul(replicas)
+		requests[name] = quantity
+	}
+	return requests
+}
@@ -5,8 +5,13 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 type StatefulSetAdapter struct {
@@ -25,6 +30,10 @@ func (s *StatefulSetAdapter) GetNamespace() string {
 	return s.statefulset.Namespace
 }
 
+func (s *StatefulSetAdapter) GetLabels() map[string]string {
+	return s.statefulset.GetLabels()
+}
+
 func (s *StatefulSetAdapter) GetAnnotations() map[string]string {
 	return s.statefulset.Annotations
 }
@@ -39,35 +48,96 @@ func (s *StatefulSetAdapter) IsSnoozed() bool {
 	return isSnoozed
 }
 
-func (s *StatefulSetAdapter) Snooze(ctx context.Context, r client.Client) error {
-	replicas := strconv.Itoa(int(*s.statefulset.Spec.Replicas))
-	s.statefulset.Spec.Replicas = ptr.To[int32](0)
-	annotations := s.GetAnnotations()
-	if annotations == nil {
-		annotations = make(map[string]string)
+// IsDrifted reports whether a snoozed statefulset was scaled back up outside of kube-snooze.
+func (s *StatefulSetAdapter) IsDrifted() bool {
+	return s.IsSnoozed() && ptr.Deref(s.statefulset.Spec.Replicas, 1) > 0
+}
+
+// IsSettled reports whether a snoozed statefulset has no replicas left, or an awake
+// one has all of its replicas ready.
+func (s *StatefulSetAdapter) IsSettled() bool {
+	if s.statefulset.Status.ObservedGeneration < s.statefulset.Generation {
+		return false
+	}
+	if s.IsSnoozed() {
+		return s.statefulset.Status.Replicas == 0
 	}
-	annotations[BackupReplicasKey] = replicas
-	s.SetAnnotations(annotations)
-	return r.Update(ctx, s.statefulset)
+	return s.statefulset.Status.ReadyReplicas >= ptr.Deref(s.statefulset.Spec.Replicas, 1)
+}
+
+// Availability returns the available replicas of the statefulset and the replicas
+// it should run.
+func (s *StatefulSetAdapter) Availability() (int32, int32) {
+	return s.statefulset.Status.AvailableReplicas, ptr.Deref(s.statefulset.Spec.Replicas, 1)
+}
+
+func (s *StatefulSetAdapter) PodSelector() *metav1.LabelSelector {
+	return s.statefulset.Spec.Selector
+}
+
+func (s *StatefulSetAdapter) Snooze(ctx context.Context, r client.Client, owner string) error {
+	return utils.PatchWithRetry(ctx, r, s.statefulset, func() error {
+		annotations := s.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		// Keep an existing backup so a retry never records the already zeroed count
+		if _, exists := annotations[BackupReplicasKey]; !exists {
+			annotations[BackupReplicasKey] = strconv.Itoa(int(ptr.Deref(s.statefulset.Spec.Replicas, 1)))
+		}
+		annotations[schedulingv1alpha1.SnoozedByAnnotation] = owner
+		s.statefulset.Spec.Replicas = ptr.To[int32](0)
+		s.SetAnnotations(annotations)
+		labels := s.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[SnoozedLabel] = "true"
+		s.statefulset.SetLabels(labels)
+		return nil
+	})
 }
 
 func (s *StatefulSetAdapter) Wake(ctx context.Context, r client.Client) error {
-	annotations := s.GetAnnotations()
-	if storedReplicas, exists := annotations[BackupReplicasKey]; exists {
+	return utils.PatchWithRetry(ctx, r, s.statefulset, func() error {
+		annotations := s.GetAnnotations()
+		storedReplicas, exists := annotations[BackupReplicasKey]
+		if !exists {
+			return nil
+		}
+
 		desiredReplicas, err := strconv.ParseInt(storedReplicas, 10, 32)
 		if err != nil {
 			return err
 		}
 
-		if desiredReplicas > 0 {
+		// An overridden statefulset is already running at the size it was scaled to
+		if _, overridden := annotations[OverrideKey]; !overridden && desiredReplicas > 0 {
 			s.statefulset.Spec.Replicas = ptr.To(int32(desiredReplicas))
 		}
 
+		// Clean up annotations and the snoozed label
 		delete(annotations, BackupReplicasKey)
+		delete(annotations, OverrideKey)
+		delete(annotations, schedulingv1alpha1.SnoozedByAnnotation)
 		s.SetAnnotations(annotations)
-	}
+		labels := s.GetLabels()
+		delete(labels, SnoozedLabel)
+		s.statefulset.SetLabels(labels)
+		return nil
+	})
+}
+
+// SnoozedRequests returns the resources the replicas scaled down by the snooze
+// would request.
+func (s *StatefulSetAdapter) SnoozedRequests() corev1.ResourceList {
+	return snoozedRequests(s.GetAnnotations(), &s.statefulset.Spec.Template)
+}
 
-	return r.Update(ctx, s.statefulset)
+// Annotate sets the annotation key of the statefulset to value, or removes it when
+// value is empty.
+func (s *StatefulSetAdapter) Annotate(ctx context.Context, r client.Client, key, value string) error {
+	return utils.PatchAnnotation(ctx, r, s.statefulset, key, value)
 }
 
 func (s *StatefulSetAdapter) GetResourceType() string {
@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// intent is what a window wants for the resources it selects at a point in time.
+type intent int
+
+const (
+	// intentNone leaves the resources to the other windows selecting them, or
+	// awake when there are none.
+	intentNone intent = iota
+	// intentAsleep keeps the resources snoozed.
+	intentAsleep
+	// intentAwake holds the resources awake through a wake override.
+	intentAwake
+)
+
+// peerWindow is another window of the namespace that selects some of the
+// resources of the window being reconciled.
+type peerWindow struct {
+	name     string
+	priority int32
+	intent   intent
+	// selects holds the resourceKey of every resource the window selects
+	selects map[string]bool
+}
+
+// contender is a window with an intent for a shared resource.
+type contender struct {
+	name     string
+	priority int32
+	intent   intent
+}
+
+func resourceKey(resource snoozetypes.SnoozableResource) string {
+	return resource.GetResourceType() + "/" + resource.GetName()
+}
+
+// resolveConflicts settles the resources the window shares with other windows.
+// The highest priority window that is snoozing a resource or holding it awake
+// decides; on a tie any snoozing window keeps it asleep, and the window that
+// snoozed it keeps owning it. Resources decided by another window are removed
+// from resourceManager and, unless the window is suspended or in dry-run mode,
+// brought to that window's outcome on its behalf, since the deciding window may
+// not reconcile again before its own next transition.
+func (r *SnoozeWindowReconciler) resolveConflicts(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, self intent, now time.Time) error {
+	logger := logf.FromContext(ctx)
+
+	peers, err := r.findPeers(ctx, snoozeWindow, resourceManager, now)
+	if err != nil {
+		return err
+	}
+	r.setConflictCondition(snoozeWindow, peers)
+	if len(peers) == 0 {
+		return nil
+	}
+
+	type decision struct {
+		outcome intent
+		owner   string
+	}
+	decisions := make(map[string]decision)
+	contested := resourceManager.Exclude(func(resource snoozetypes.SnoozableResource) bool {
+		contenders := []contender{{name: snoozeWindow.Name, priority: snoozeWindow.Spec.Priority, intent: self}}
+		for _, peer := range peers {
+			if peer.selects[resourceKey(resource)] {
+				contenders = append(contenders, contender{name: peer.name, priority: peer.priority, intent: peer.intent})
+			}
+		}
+		outcome, owner := decide(contenders, resource.GetAnnotations()[schedulingv1alpha1.SnoozedByAnnotation])
+		decisions[resourceKey(resource)] = decision{outcome: outcome, owner: owner}
+		if outcome == intentAsleep {
+			return owner != snoozeWindow.Name
+		}
+		return outcome != self
+	})
+
+	if snoozeWindow.Spec.Suspended || snoozeWindow.Spec.DryRun {
+		return nil
+	}
+	for _, resource := range contested {
+		decided := decisions[resourceKey(resource)]
+		annotations := resource.GetAnnotations()
+		switch decided.outcome {
+		case intentAsleep:
+			if resource.IsSnoozed() && !resource.IsDrifted() && annotations[schedulingv1alpha1.SnoozedByAnnotation] == decided.owner {
+				continue
+			}
+			if _, wakeOverride, _ := utils.WakeOverrideUntil(annotations, now); wakeOverride {
+				continue
+			}
+			if _, overridden := annotations[workloads.OverrideKey]; overridden {
+				continue
+			}
+			logger.Info("Snoozing shared resource for another window",
+				"type", resource.GetResourceType(), "name", resource.GetName(), "owner", decided.owner)
+			if err := resourceManager.SnoozeResource(ctx, r.Client, resource, decided.owner); err != nil {
+				return err
+			}
+		default:
+			if !resource.IsSnoozed() {
+				continue
+			}
+			logger.Info("Waking shared resource no window keeps asleep",
+				"type", resource.GetResourceType(), "name", resource.GetName())
+			if err := resourceManager.WakeResource(ctx, r.Client, resource); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decide returns the outcome for a resource wanted by contenders and, when it
+// is snoozed, the window owning it. currentOwner keeps owning a resource it
+// snoozed as long as it is among the windows keeping it asleep.
+func decide(contenders []contender, currentOwner string) (intent, string) {
+	contenders = slices.DeleteFunc(contenders, func(c contender) bool { return c.intent == intentNone })
+	if len(contenders) == 0 {
+		return intentNone, ""
+	}
+
+	top := slices.MaxFunc(contenders, func(a, b contender) int { return int(a.priority) - int(b.priority) }).priority
+	var asleep []string
+	for _, c := range contenders {
+		if c.priority == top && c.intent == intentAsleep {
+			asleep = append(asleep, c.name)
+		}
+	}
+	if len(asleep) == 0 {
+		return intentAwake, ""
+	}
+	if slices.Contains(asleep, currentOwner) {
+		return intentAsleep, currentOwner
+	}
+	// The window being reconciled comes first, so it takes over before others
+	return intentAsleep, asleep[0]
+}
+
+// findPeers returns the other windows of the namespace that select some of the
+// window's resources and are neither suspended nor in dry-run mode.
+func (r *SnoozeWindowReconciler) findPeers(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) ([]peerWindow, error) {
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := r.List(ctx, &snoozeWindows, client.InNamespace(snoozeWindow.Namespace)); err != nil {
+		return nil, err
+	}
+
+	own := make(map[string]bool)
+	for _, resource := range resourceManager.Resources() {
+		own[resourceKey(resource)] = true
+	}
+
+	var peers []peerWindow
+	for i := range snoozeWindows.Items {
+		other := &snoozeWindows.Items[i]
+		if other.Name == snoozeWindow.Name || other.Spec.Suspended || other.Spec.DryRun {
+			continue
+		}
+		otherManager, err := adapter.ForWindow(ctx, r.Client, other)
+		if err != nil {
+			return nil, err
+		}
+
+		selects := make(map[string]bool)
+		for _, resource := range otherManager.Resources() {
+			if own[resourceKey(resource)] {
+				selects[resourceKey(resource)] = true
+			}
+		}
+		if len(selects) == 0 {
+			continue
+		}
+		peers = append(peers, peerWindow{
+			name:     other.Name,
+			priority: other.Spec.Priority,
+			intent:   r.windowIntent(ctx, other, now),
+			selects:  selects,
+		})
+	}
+	return peers, nil
+}
+
+// windowIntent evaluates what another window wants at now, in the order its
+// own reconcile gives precedence to its settings.
+func (r *SnoozeWindowReconciler) windowIntent(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, now time.Time) intent {
+	if _, wakeOverride, _ := utils.WakeOverrideUntil(snoozeWindow.GetAnnotations(), now); wakeOverride {
+		return intentAwake
+	}
+	if _, manualSnooze, _ := utils.SnoozeOverrideUntil(snoozeWindow.GetAnnotations(), now); manualSnooze {
+		return intentAsleep
+	}
+	if suspendUntil := snoozeWindow.Spec.SuspendUntil; suspendUntil != nil && now.Before(suspendUntil.Time) {
+		return intentAsleep
+	}
+	// An idle snooze has no end of its own, only the wake override above ends it
+	if snoozeWindow.Spec.IdleSnooze != nil && snoozeWindow.Status.IdleSnoozeTime != nil {
+		return intentAsleep
+	}
+
+	schedule, err := utils.LoadSchedule(ctx, r.Client, snoozeWindow)
+	if err != nil {
+		return intentNone
+	}
+	if active, _ := schedule.Active(now); active {
+		return intentAsleep
+	}
+	return intentNone
+}
+
+// setConflictCondition reports the windows sharing resources with snoozeWindow.
+func (r *SnoozeWindowReconciler) setConflictCondition(snoozeWindow *schedulingv1alpha1.SnoozeWindow, peers []peerWindow) {
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoSharedResources",
+		Message:            "No other SnoozeWindow selects the window's resources",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if len(peers) > 0 {
+		names := make([]string, 0, len(peers))
+		for _, peer := range peers {
+			names = append(names, fmt.Sprintf("%s (priority %d, %d resource(s))", peer.name, peer.priority, len(peer.selects)))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SharedResources"
+		condition.Message = "Shares resources with SnoozeWindow " + strings.Join(names, ", ") +
+			". The highest priority window snoozing or holding them awake decides, and on a tie they are kept asleep"
+	}
+
+	if condition.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionConflict) {
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "Conflict", condition.Message)
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+}
+
+// findPeerSnoozeWindows maps a SnoozeWindow event to the other windows of its
+// namespace, which may share resources with it.
+func (r *SnoozeWindowReconciler) findPeerSnoozeWindows(ctx context.Context, obj client.Object) []reconcile.Request {
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := r.List(ctx, &snoozeWindows, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SnoozeWindows", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, snoozeWindow := range snoozeWindows.Items {
+		if snoozeWindow.Name == obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snoozeWindow.Name, Namespace: snoozeWindow.Namespace},
+		})
+	}
+	return requests
+}
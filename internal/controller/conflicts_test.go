@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/jobs"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Conflict resolution", func() {
+	It("Should keep a resource asleep on a tie and keep its current owner", func() {
+		outcome, owner := decide([]contender{
+			{name: "nightly", intent: intentAsleep},
+			{name: "weekend", intent: intentAsleep},
+			{name: "release", intent: intentAwake},
+		}, "weekend")
+		Expect(outcome).To(Equal(intentAsleep))
+		Expect(owner).To(Equal("weekend"))
+	})
+
+	It("Should let a higher priority window hold a resource awake", func() {
+		outcome, _ := decide([]contender{
+			{name: "nightly", intent: intentAsleep},
+			{name: "release", priority: 10, intent: intentAwake},
+		}, "nightly")
+		Expect(outcome).To(Equal(intentAwake))
+	})
+
+	It("Should snooze a shared resource on behalf of the window keeping it asleep", func() {
+		ctx := context.Background()
+		selector := map[string]string{"app": "api"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: selector},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+		}
+		nightly := &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{LabelSelector: selector},
+		}
+		weekend := &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "weekend", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				LabelSelector: selector,
+				SuspendUntil:  &metav1.Time{Time: time.Now().Add(time.Hour)},
+			},
+		}
+		reconciler := &SnoozeWindowReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, nightly.DeepCopy(), weekend).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		resourceManager, err := adapter.ForWindow(ctx, reconciler.Client, nightly)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.resolveConflicts(ctx, nightly, resourceManager, intentNone, time.Now())).To(Succeed())
+
+		Expect(resourceManager.Resources()).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(nightly.Status.Conditions, schedulingv1alpha1.ConditionConflict)).To(BeTrue())
+
+		stored := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(deployment), stored)).To(Succeed())
+		Expect(stored.Spec.Replicas).To(HaveValue(Equal(int32(0))))
+		Expect(stored.Annotations).To(HaveKeyWithValue(schedulingv1alpha1.SnoozedByAnnotation, "weekend"))
+	})
+
+	It("Should leave a shared resource snoozed by an idle window asleep", func() {
+		ctx := context.Background()
+		selector := map[string]string{"app": "api"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api",
+				Namespace: "default",
+				Labels:    selector,
+				Annotations: map[string]string{
+					workloads.BackupReplicasKey:            "3",
+					schedulingv1alpha1.SnoozedByAnnotation: "idle",
+				},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		}
+		nightly := &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{LabelSelector: selector},
+		}
+		idle := &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				LabelSelector: selector,
+				IdleSnooze:    &schedulingv1alpha1.IdleSnoozeSpec{Threshold: resource.MustParse("50m")},
+			},
+			Status: schedulingv1alpha1.SnoozeWindowStatus{IdleSnoozeTime: &metav1.Time{Time: time.Now().Add(-time.Hour)}},
+		}
+		reconciler := &SnoozeWindowReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, nightly.DeepCopy(), idle).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		Expect(reconciler.windowIntent(ctx, idle, time.Now())).To(Equal(intentAsleep))
+
+		resourceManager, err := adapter.ForWindow(ctx, reconciler.Client, nightly)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.resolveConflicts(ctx, nightly, resourceManager, intentNone, time.Now())).To(Succeed())
+
+		Expect(resourceManager.Resources()).To(BeEmpty())
+		stored := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(deployment), stored)).To(Succeed())
+		Expect(stored.Spec.Replicas).To(HaveValue(Equal(int32(0))))
+		Expect(stored.Annotations).To(HaveKeyWithValue(schedulingv1alpha1.SnoozedByAnnotation, "idle"))
+
+		By("holding the idle window's resources awake with a wake override")
+		idle.Annotations = map[string]string{
+			schedulingv1alpha1.WakeUntilAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		}
+		Expect(reconciler.windowIntent(ctx, idle, time.Now())).To(Equal(intentAwake))
+	})
+
+	It("Should only wake the resources the window snoozed", func() {
+		ctx := context.Background()
+		snoozedBy := func(name, owner string) *appsv1.Deployment {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   "default",
+					Annotations: map[string]string{workloads.BackupReplicasKey: "2"},
+				},
+				Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+			}
+			if owner != "" {
+				deployment.Annotations[schedulingv1alpha1.SnoozedByAnnotation] = owner
+			}
+			return deployment
+		}
+		own, legacy, other := snoozedBy("api", "nightly"), snoozedBy("worker", ""), snoozedBy("web", "weekend")
+		suspended := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 6 * * *", Suspend: ptr.To(true)},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(own, legacy, other, suspended).Build()
+
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.Owner = "nightly"
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(own))
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(legacy))
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(other))
+		resourceManager.AddResource(jobs.NewCronJobAdapter(suspended))
+		Expect(resourceManager.Owned()).To(HaveLen(2))
+
+		Expect(resourceManager.WakeAll(ctx, c)).To(Succeed())
+
+		for name, replicas := range map[string]int32{"api": 2, "worker": 2, "web": 0} {
+			stored := &appsv1.Deployment{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, stored)).To(Succeed())
+			Expect(stored.Spec.Replicas).To(HaveValue(Equal(replicas)), name)
+		}
+		storedCronJob := &batchv1.CronJob{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(suspended), storedCronJob)).To(Succeed())
+		Expect(storedCronJob.Spec.Suspend).To(HaveValue(BeTrue()))
+	})
+})
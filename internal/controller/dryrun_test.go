@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Dry run", func() {
+	It("Should report the resources it would snooze without changing them", func() {
+		ctx := context.Background()
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+		}
+		snoozeWindow := &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{DryRun: true},
+		}
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &SnoozeWindowReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment.DeepCopy()).Build(),
+			Recorder: recorder,
+		}
+
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.DryRun = true
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(deployment))
+
+		Expect(resourceManager.SnoozeAll(ctx, reconciler.Client)).To(Succeed())
+		reconciler.recordOutcome(ctx, snoozeWindow, resourceManager, time.Now())
+
+		Expect(snoozeWindow.Status.DryRunChanges).To(ConsistOf(schedulingv1alpha1.DryRunChange{
+			Action: schedulingv1alpha1.ActionSnooze,
+			Kind:   schedulingv1alpha1.ResourceTypeDeployment,
+			Name:   "api",
+		}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Would snooze deployment api")))
+
+		stored := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(deployment), stored)).To(Succeed())
+		Expect(stored.Spec.Replicas).To(HaveValue(Equal(int32(3))))
+		Expect(stored.Annotations).NotTo(HaveKey(workloads.BackupReplicasKey))
+	})
+})
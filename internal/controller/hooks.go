@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+// defaultHookTimeout is how long a hook Job may run when the hook does not
+// set a timeout.
+const defaultHookTimeout = 10 * time.Minute
+
+// Phases of the lifecycle hooks, as set in the hookPhaseLabel of their Jobs.
+const (
+	hookPhasePreSnooze = "pre-snooze"
+	hookPhasePostWake  = "post-wake"
+)
+
+// hookPhaseLabel tells the pre-snooze and post-wake Jobs of a hook apart.
+const hookPhaseLabel = "kube-snooze/hook-phase"
+
+// hookOutcome is where the hooks of a transition stand.
+type hookOutcome int
+
+const (
+	hooksSucceeded hookOutcome = iota
+	hooksRunning
+	hooksFailed
+)
+
+// runHooks runs the hooks of a transition of the window one after the other,
+// starting the Job of the first one that has none yet for the transition key.
+// A hook whose Job failed stops the hooks after it unless its failure policy is
+// Ignore. The LifecycleHooks condition reports the outcome, and each change of
+// it is announced with an Event. Hooks are not run by a dry-run window.
+func (r *SnoozeWindowReconciler) runHooks(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, phase string, hooks []schedulingv1alpha1.LifecycleHook, key time.Time) (hookOutcome, error) {
+	logger := logf.FromContext(ctx)
+	if len(hooks) == 0 {
+		return hooksSucceeded, nil
+	}
+	if snoozeWindow.Spec.DryRun {
+		logger.Info("Skipping lifecycle hooks of dry-run window", "phase", phase)
+		return hooksSucceeded, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionLifecycleHooks,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Succeeded",
+		Message:            fmt.Sprintf("The %s hooks succeeded", phase),
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	eventType, outcome := corev1.EventTypeNormal, hooksSucceeded
+	var ignored []string
+
+	for _, hook := range hooks {
+		job, err := r.hookJob(ctx, snoozeWindow, phase, hook, key)
+		if err != nil {
+			return hooksRunning, err
+		}
+
+		finished, failure := jobFinished(job)
+		if !finished {
+			logger.Info("Waiting for lifecycle hook", "phase", phase, "hook", hook.Name, "job", job.Name)
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = "Running"
+			condition.Message = fmt.Sprintf("Running the %s hook %q as Job %s", phase, hook.Name, job.Name)
+			outcome = hooksRunning
+			break
+		}
+		if failure == "" {
+			continue
+		}
+		if hook.FailurePolicy == schedulingv1alpha1.HookFailurePolicyIgnore {
+			ignored = append(ignored, hook.Name)
+			continue
+		}
+
+		logger.Info("Lifecycle hook failed", "phase", phase, "hook", hook.Name, "job", job.Name, "reason", failure)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Failed"
+		condition.Message = fmt.Sprintf("The %s hook %q failed, Job %s: %s", phase, hook.Name, job.Name, failure)
+		eventType, outcome = corev1.EventTypeWarning, hooksFailed
+		break
+	}
+	if outcome == hooksSucceeded && len(ignored) > 0 {
+		condition.Message = fmt.Sprintf("The %s hooks succeeded, ignoring the failure of %s", phase, strings.Join(ignored, ", "))
+	}
+
+	previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionLifecycleHooks)
+	if previous == nil || previous.Message != condition.Message {
+		r.Recorder.Event(snoozeWindow, eventType, "Hook"+condition.Reason, condition.Message)
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+	return outcome, nil
+}
+
+// hookJob returns the Job of hook for the transition key, creating it from the
+// hook's job template when it does not exist yet. The finished Jobs of earlier
+// transitions are deleted once the new one is created.
+func (r *SnoozeWindowReconciler) hookJob(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, phase string, hook schedulingv1alpha1.LifecycleHook, key time.Time) (*batchv1.Job, error) {
+	name := hookJobName(snoozeWindow.Name, hook.Name, phase, key)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: snoozeWindow.Namespace}, job)
+	if err == nil || !errors.IsNotFound(err) {
+		return job, err
+	}
+
+	var template batchv1.CronJob
+	if err := r.Get(ctx, types.NamespacedName{Name: hook.JobTemplateRef.Name, Namespace: snoozeWindow.Namespace}, &template); err != nil {
+		return nil, err
+	}
+
+	hookLabels := map[string]string{
+		schedulingv1alpha1.HookLabel:   hook.Name,
+		schedulingv1alpha1.WindowLabel: snoozeWindow.Name,
+		hookPhaseLabel:                 phase,
+	}
+	job = &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   snoozeWindow.Namespace,
+			Labels:      map[string]string{},
+			Annotations: template.Spec.JobTemplate.Annotations,
+		},
+		Spec: *template.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+	for k, v := range template.Spec.JobTemplate.Labels {
+		job.Labels[k] = v
+	}
+	for k, v := range hookLabels {
+		job.Labels[k] = v
+	}
+
+	// The Job is deleted by the next run of the hook, not by its TTL, so its
+	// outcome stays around for the window to read
+	job.Spec.TTLSecondsAfterFinished = nil
+	timeout := defaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+	if deadline := int64(timeout.Seconds()); job.Spec.ActiveDeadlineSeconds == nil || deadline < *job.Spec.ActiveDeadlineSeconds {
+		job.Spec.ActiveDeadlineSeconds = ptr.To(deadline)
+	}
+	if r.Scheme != nil {
+		if err := controllerutil.SetControllerReference(snoozeWindow, job, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	logf.FromContext(ctx).Info("Starting lifecycle hook", "phase", phase, "hook", hook.Name, "job", name)
+	if err := r.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	var previous batchv1.JobList
+	if err := r.List(ctx, &previous, client.InNamespace(snoozeWindow.Namespace), client.MatchingLabels(hookLabels)); err != nil {
+		return nil, err
+	}
+	for _, earlier := range previous.Items {
+		if finished, _ := jobFinished(&earlier); earlier.Name == name || !finished {
+			continue
+		}
+		if err := r.Delete(ctx, &earlier, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// hookJobName names the Job of a hook for the transition key, keeping it short
+// enough for the job-name label of its pods.
+func hookJobName(window, hook, phase string, key time.Time) string {
+	suffix := "-" + strings.SplitN(phase, "-", 2)[0] + "-" + strconv.FormatInt(key.Unix(), 36)
+	prefix := window + "-" + hook
+	if limit := 63 - len(suffix); len(prefix) > limit {
+		prefix = strings.TrimRight(prefix[:limit], "-.")
+	}
+	return prefix + suffix
+}
+
+// jobFinished reports whether job completed or failed, and why it failed.
+func jobFinished(job *batchv1.Job) (bool, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, ""
+		case batchv1.JobFailed:
+			return true, cmp.Or(condition.Message, condition.Reason, string(batchv1.JobFailed))
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+)
+
+var _ = Describe("Lifecycle hooks", func() {
+	var (
+		ctx          context.Context
+		key          time.Time
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+		reconciler   *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", UID: "nightly-uid"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				PreSnoozeHooks: []schedulingv1alpha1.LifecycleHook{{
+					Name:           "drain",
+					JobTemplateRef: corev1.LocalObjectReference{Name: "drain-queue"},
+					Timeout:        &metav1.Duration{Duration: 5 * time.Minute},
+				}},
+			},
+		}
+		template := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "drain-queue", Namespace: "default"},
+			Spec: batchv1.CronJobSpec{
+				Schedule: "0 0 * * *",
+				Suspend:  ptr.To(true),
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						ActiveDeadlineSeconds:   ptr.To[int64](3600),
+						TTLSecondsAfterFinished: ptr.To[int32](60),
+					},
+				},
+			},
+		}
+		reconciler = &SnoozeWindowReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(template).Build(),
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	run := func() hookOutcome {
+		outcome, err := reconciler.runHooks(ctx, snoozeWindow, hookPhasePreSnooze, snoozeWindow.Spec.PreSnoozeHooks, key)
+		Expect(err).NotTo(HaveOccurred())
+		return outcome
+	}
+
+	finish := func(conditionType batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		name := hookJobName(snoozeWindow.Name, "drain", hookPhasePreSnooze, key)
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, job)).To(Succeed())
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type: conditionType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded",
+		})
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("Should leave the suspended template of a hook out of the window's resources", func() {
+		report := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 6 * * *"},
+		}
+		Expect(reconciler.Create(ctx, report)).To(Succeed())
+
+		// The window selects every CronJob of the namespace, the template included
+		resourceManager, err := adapter.ForWindow(ctx, reconciler.Client, snoozeWindow)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resourceManager.Resources()).To(ConsistOf(HaveField("GetName()", "report")))
+		Expect(resourceManager.Owned()).To(BeEmpty())
+	})
+
+	It("Should start the hook's Job from its template and wait for it to complete", func() {
+		Expect(run()).To(Equal(hooksRunning))
+		Expect(meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionLifecycleHooks).Status).
+			To(Equal(metav1.ConditionUnknown))
+
+		var jobs batchv1.JobList
+		Expect(reconciler.List(ctx, &jobs, client.MatchingLabels{schedulingv1alpha1.HookLabel: "drain"})).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+		Expect(jobs.Items[0].Labels).To(HaveKeyWithValue(schedulingv1alpha1.WindowLabel, "nightly"))
+		Expect(jobs.Items[0].Spec.ActiveDeadlineSeconds).To(HaveValue(BeEquivalentTo(300)))
+		Expect(jobs.Items[0].Spec.TTLSecondsAfterFinished).To(BeNil())
+		Expect(metav1.IsControlledBy(&jobs.Items[0], snoozeWindow)).To(BeTrue())
+
+		finish(batchv1.JobComplete)
+		Expect(run()).To(Equal(hooksSucceeded))
+		Expect(meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionLifecycleHooks)).To(BeTrue())
+	})
+
+	It("Should hold the transition back on a failed hook unless its failure is ignored", func() {
+		run()
+		finish(batchv1.JobFailed)
+		Expect(run()).To(Equal(hooksFailed))
+		Expect(meta.IsStatusConditionFalse(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionLifecycleHooks)).To(BeTrue())
+
+		snoozeWindow.Spec.PreSnoozeHooks[0].FailurePolicy = schedulingv1alpha1.HookFailurePolicyIgnore
+		Expect(run()).To(Equal(hooksSucceeded))
+	})
+
+	It("Should delete the finished Job of the previous transition", func() {
+		run()
+		finish(batchv1.JobComplete)
+
+		key = key.Add(24 * time.Hour)
+		Expect(run()).To(Equal(hooksRunning))
+
+		var jobs batchv1.JobList
+		Expect(reconciler.List(ctx, &jobs, client.MatchingLabels{schedulingv1alpha1.HookLabel: "drain"})).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+		Expect(jobs.Items[0].Name).To(Equal(hookJobName("nightly", "drain", hookPhasePreSnooze, key)))
+	})
+})
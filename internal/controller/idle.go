@@ -0,0 +1,278 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+const (
+	// defaultIdleLookback is how long workloads have to stay idle before they
+	// are snoozed, when spec.idleSnooze.lookback is not set.
+	defaultIdleLookback = 30 * time.Minute
+	// defaultMinAwake is how long workloads stay awake after a wake, however
+	// idle, when spec.idleSnooze.minAwake is not set.
+	defaultMinAwake = time.Hour
+	// idleSampleInterval is how often the activity of awake workloads is
+	// measured.
+	idleSampleInterval = time.Minute
+	// prometheusTimeout bounds a single Prometheus query.
+	prometheusTimeout = 30 * time.Second
+)
+
+// podMetricsList is the list kind of the Kubernetes metrics API's pod usage.
+var podMetricsList = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// reconcileIdleSnooze follows the activity of the workloads of a window with
+// spec.idleSnooze. While they are awake their activity is measured, and once
+// it stayed below the threshold for the lookback, and the workloads have been
+// awake for the minimum, they are snoozed until a wake override on the window.
+// scheduled tells that the schedule or an on-demand snooze holds them asleep
+// already. It reports whether the workloads are snoozed for being idle, whether
+// such a snooze just ended and when their activity is to be measured next.
+func (r *SnoozeWindowReconciler) reconcileIdleSnooze(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, scheduled, windowOverride bool, now time.Time) (bool, bool, time.Time) {
+	logger := logf.FromContext(ctx)
+	status := &snoozeWindow.Status
+	spec := snoozeWindow.Spec.IdleSnooze
+	wasSnoozed := status.IdleSnoozeTime != nil
+
+	if spec == nil {
+		status.IdleSince, status.IdleSnoozeTime = nil, nil
+		meta.RemoveStatusCondition(&status.Conditions, schedulingv1alpha1.ConditionIdleSnooze)
+		return false, wasSnoozed, time.Time{}
+	}
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionIdleSnooze,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	switch {
+	case windowOverride:
+		if wasSnoozed {
+			r.Recorder.Event(snoozeWindow, corev1.EventTypeNormal, "IdleSnoozeEnded", "Waking idle workloads for the wake override")
+		}
+		status.IdleSince, status.IdleSnoozeTime = nil, nil
+		condition.Reason = "WakeOverride"
+		condition.Message = "Workloads are held awake by a wake override"
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return false, wasSnoozed, time.Time{}
+
+	case wasSnoozed:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Idle"
+		condition.Message = fmt.Sprintf("Workloads snoozed for being idle at %s, set the %s annotation to wake them",
+			status.IdleSnoozeTime.UTC().Format(time.RFC3339), schedulingv1alpha1.WakeUntilAnnotation)
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return true, false, time.Time{}
+
+	case scheduled:
+		status.IdleSince = nil
+		condition.Reason = "Scheduled"
+		condition.Message = "Workloads are snoozed by the schedule"
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return false, false, time.Time{}
+	}
+
+	nextSample := now.Add(idleSampleInterval)
+	activity, err := r.measureActivity(ctx, snoozeWindow, resourceManager, now)
+	if err != nil {
+		logger.Error(err, "Failed to measure the activity of idle-snoozed workloads")
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "MeasurementFailed"
+		condition.Message = err.Error()
+		if previous := meta.FindStatusCondition(status.Conditions, schedulingv1alpha1.ConditionIdleSnooze); previous == nil || previous.Message != condition.Message {
+			r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "IdleMeasurementFailed", err.Error())
+		}
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return false, false, nextSample
+	}
+
+	if activity >= spec.Threshold.AsApproximateFloat64() {
+		status.IdleSince = nil
+		condition.Reason = "Active"
+		condition.Message = fmt.Sprintf("Workloads are active, above %s", spec.Threshold.String())
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return false, false, nextSample
+	}
+
+	if status.IdleSince == nil {
+		status.IdleSince = &metav1.Time{Time: now.Truncate(time.Second)}
+	}
+	snoozeAt := idleSnoozeAt(snoozeWindow)
+	if now.Before(snoozeAt) {
+		condition.Reason = "Idle"
+		condition.Message = fmt.Sprintf("Workloads idle since %s, snoozing at %s",
+			status.IdleSince.UTC().Format(time.RFC3339), snoozeAt.UTC().Format(time.RFC3339))
+		meta.SetStatusCondition(&status.Conditions, condition)
+		if snoozeAt.Before(nextSample) {
+			nextSample = snoozeAt
+		}
+		return false, false, nextSample
+	}
+
+	logger.Info("Snoozing idle workloads", "idleSince", status.IdleSince.Time)
+	r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "IdleSnooze", "Snoozing workloads idle since %s",
+		status.IdleSince.UTC().Format(time.RFC3339))
+	status.IdleSnoozeTime = &metav1.Time{Time: now.Truncate(time.Second)}
+	status.IdleSince = nil
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Idle"
+	condition.Message = fmt.Sprintf("Workloads snoozed for being idle at %s, set the %s annotation to wake them",
+		status.IdleSnoozeTime.UTC().Format(time.RFC3339), schedulingv1alpha1.WakeUntilAnnotation)
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return true, false, time.Time{}
+}
+
+// idleSnoozeAt returns when the workloads of a window idle since
+// status.idleSince are snoozed: once idle for the lookback, and no sooner than
+// the minimum awake time after the window last woke them or was created.
+func idleSnoozeAt(snoozeWindow *schedulingv1alpha1.SnoozeWindow) time.Time {
+	spec := snoozeWindow.Spec.IdleSnooze
+	lookback, minAwake := defaultIdleLookback, defaultMinAwake
+	if spec.Lookback != nil {
+		lookback = spec.Lookback.Duration
+	}
+	if spec.MinAwake != nil {
+		minAwake = spec.MinAwake.Duration
+	}
+
+	awakeSince := snoozeWindow.CreationTimestamp.Time
+	if lastWake := snoozeWindow.Status.LastWakeTime; lastWake != nil && lastWake.After(awakeSince) {
+		awakeSince = lastWake.Time
+	}
+	snoozeAt := snoozeWindow.Status.IdleSince.Add(lookback)
+	if awake := awakeSince.Add(minAwake); awake.After(snoozeAt) {
+		snoozeAt = awake
+	}
+	return snoozeAt
+}
+
+// measureActivity returns the current activity of the window's workloads,
+// measured by the source of spec.idleSnooze.
+func (r *SnoozeWindowReconciler) measureActivity(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) (float64, error) {
+	if snoozeWindow.Spec.IdleSnooze.Source == schedulingv1alpha1.IdleSourcePrometheus {
+		return r.queryActivity(ctx, snoozeWindow, now)
+	}
+	return r.cpuUsage(ctx, resourceManager)
+}
+
+// cpuUsage sums the CPU cores used by the pods of the window's awake
+// workloads, as reported by the metrics API.
+func (r *SnoozeWindowReconciler) cpuUsage(ctx context.Context, resourceManager *adapter.ResourceManager) (float64, error) {
+	var usage float64
+	counted := make(map[string]bool)
+	for _, candidate := range resourceManager.Resources() {
+		workload, ok := candidate.(snoozetypes.ReplicatedResource)
+		if !ok || workload.IsSnoozed() || workload.PodSelector() == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(workload.PodSelector())
+		if err != nil {
+			return 0, err
+		}
+
+		podMetrics := &unstructured.UnstructuredList{}
+		podMetrics.SetGroupVersionKind(podMetricsList)
+		if err := r.List(ctx, podMetrics, client.InNamespace(workload.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return 0, err
+		}
+		for _, pod := range podMetrics.Items {
+			// Workloads whose selectors overlap share pods
+			if counted[pod.GetName()] {
+				continue
+			}
+			counted[pod.GetName()] = true
+
+			containers, _, _ := unstructured.NestedSlice(pod.Object, "containers")
+			for _, container := range containers {
+				value, _, _ := unstructured.NestedString(container.(map[string]any), "usage", "cpu")
+				if value == "" {
+					continue
+				}
+				quantity, err := resource.ParseQuantity(value)
+				if err != nil {
+					return 0, err
+				}
+				usage += quantity.AsApproximateFloat64()
+			}
+		}
+	}
+	return usage, nil
+}
+
+// queryActivity runs the window's PromQL query and sums the values of its
+// result.
+func (r *SnoozeWindowReconciler) queryActivity(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, now time.Time) (float64, error) {
+	if r.PrometheusURL == "" {
+		return 0, errors.New("no Prometheus to query, the operator's --prometheus-url is not set")
+	}
+
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(snoozeWindow.Spec.IdleSnooze.Query)
+	if err != nil {
+		return 0, err
+	}
+	var query strings.Builder
+	if err := tmpl.Execute(&query, struct{ Namespace, Window string }{snoozeWindow.Namespace, snoozeWindow.Name}); err != nil {
+		return 0, err
+	}
+
+	promClient, err := promapi.NewClient(promapi.Config{Address: r.PrometheusURL})
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, prometheusTimeout)
+	defer cancel()
+	result, _, err := promv1.NewAPI(promClient).Query(ctx, query.String(), now)
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := result.(type) {
+	case model.Vector:
+		var sum float64
+		for _, sample := range value {
+			sum += float64(sample.Value)
+		}
+		return sum, nil
+	case *model.Scalar:
+		return float64(value.Value), nil
+	default:
+		return 0, fmt.Errorf("query returned a %s, expected a vector or a scalar", result.Type())
+	}
+}
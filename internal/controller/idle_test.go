@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Idle snooze", func() {
+	var (
+		ctx          context.Context
+		now          time.Time
+		activity     string
+		queries      []string
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+		recorder     *record.FakeRecorder
+		reconciler   *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		activity, queries = "0.01", nil
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			queries = append(queries, r.Form.Get("query"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[%d,%q]}]}}`,
+				now.Unix(), activity)
+		}))
+		DeferCleanup(prometheus.Close)
+
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "idle",
+				Namespace:         "staging",
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+			},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				IdleSnooze: &schedulingv1alpha1.IdleSnoozeSpec{
+					Source:    schedulingv1alpha1.IdleSourcePrometheus,
+					Query:     `sum(rate(http_requests_total{namespace="{{.Namespace}}"}[5m]))`,
+					Threshold: resource.MustParse("100m"),
+					Lookback:  &metav1.Duration{Duration: 30 * time.Minute},
+				},
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SnoozeWindowReconciler{
+			Client:        fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+			Recorder:      recorder,
+			PrometheusURL: prometheus.URL,
+		}
+	})
+
+	reconcileIdle := func(at time.Time, windowOverride bool) (bool, bool, time.Time) {
+		return reconciler.reconcileIdleSnooze(ctx, snoozeWindow, adapter.NewResourceManager(), false, windowOverride, at)
+	}
+
+	It("Should snooze once the activity stayed below the threshold for the lookback", func() {
+		snoozed, _, nextSample := reconcileIdle(now, false)
+		Expect(snoozed).To(BeFalse())
+		Expect(nextSample).To(Equal(now.Add(idleSampleInterval)))
+		Expect(queries).To(ConsistOf(`sum(rate(http_requests_total{namespace="staging"}[5m]))`))
+		Expect(snoozeWindow.Status.IdleSince.Time).To(Equal(now))
+
+		snoozed, _, _ = reconcileIdle(now.Add(29*time.Minute), false)
+		Expect(snoozed).To(BeFalse())
+
+		snoozed, _, nextSample = reconcileIdle(now.Add(30*time.Minute), false)
+		Expect(snoozed).To(BeTrue())
+		Expect(nextSample).To(BeZero())
+		Expect(snoozeWindow.Status.IdleSnoozeTime.Time).To(Equal(now.Add(30 * time.Minute)))
+		Expect(meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionIdleSnooze)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("IdleSnooze")))
+
+		// The snooze holds without measuring until a wake override ends it
+		snoozed, _, _ = reconcileIdle(now.Add(2*time.Hour), false)
+		Expect(snoozed).To(BeTrue())
+		Expect(queries).To(HaveLen(3))
+
+		snoozed, ended, _ := reconcileIdle(now.Add(3*time.Hour), true)
+		Expect(snoozed).To(BeFalse())
+		Expect(ended).To(BeTrue())
+		Expect(snoozeWindow.Status.IdleSnoozeTime).To(BeNil())
+	})
+
+	It("Should start over when the workloads become active", func() {
+		reconcileIdle(now, false)
+		Expect(snoozeWindow.Status.IdleSince).NotTo(BeNil())
+
+		activity = "0.5"
+		snoozed, _, _ := reconcileIdle(now.Add(time.Minute), false)
+		Expect(snoozed).To(BeFalse())
+		Expect(snoozeWindow.Status.IdleSince).To(BeNil())
+		Expect(meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionIdleSnooze).Reason).To(Equal("Active"))
+	})
+
+	It("Should keep the workloads awake for the minimum after a wake", func() {
+		snoozeWindow.Status.LastWakeTime = &metav1.Time{Time: now.Add(-10 * time.Minute)}
+		reconcileIdle(now, false)
+		Expect(idleSnoozeAt(snoozeWindow)).To(Equal(now.Add(50 * time.Minute)))
+
+		snoozed, _, _ := reconcileIdle(now.Add(45*time.Minute), false)
+		Expect(snoozed).To(BeFalse())
+		snoozed, _, _ = reconcileIdle(now.Add(50*time.Minute), false)
+		Expect(snoozed).To(BeTrue())
+	})
+
+	It("Should sum the CPU usage of the pods of the awake workloads", func() {
+		podMetrics := func(name string, usage ...string) *unstructured.Unstructured {
+			pod := &unstructured.Unstructured{}
+			pod.SetGroupVersionKind(schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"})
+			pod.SetName(name)
+			pod.SetNamespace("staging")
+			pod.SetLabels(map[string]string{"app": "api"})
+			var containers []any
+			for _, cpu := range usage {
+				containers = append(containers, map[string]any{"usage": map[string]any{"cpu": cpu}})
+			}
+			Expect(unstructured.SetNestedSlice(pod.Object, containers, "containers")).To(Succeed())
+			return pod
+		}
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}, meta.RESTScopeNamespace)
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(mapper).
+			WithObjects(podMetrics("api-1", "20m", "5m"), podMetrics("api-2", "30m")).Build()
+
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "staging"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+		}))
+		Expect(reconciler.cpuUsage(ctx, resourceManager)).To(BeNumerically("~", 0.055, 0.0001))
+	})
+
+	It("Should report a failed measurement without snoozing", func() {
+		reconciler.PrometheusURL = ""
+
+		snoozed, _, nextSample := reconcileIdle(now, false)
+		Expect(snoozed).To(BeFalse())
+		Expect(nextSample).To(Equal(now.Add(idleSampleInterval)))
+		Expect(meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionIdleSnooze).Status).To(Equal(metav1.ConditionUnknown))
+		Expect(recorder.Events).To(Receive(ContainSubstring("IdleMeasurementFailed")))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+// maxWakeLeadTime caps the lead time tuned from previous wakes, so a workload
+// that once took very long to start does not stay awake for most of a run.
+const maxWakeLeadTime = 2 * time.Hour
+
+// splitWakeAhead removes from resourceManager the resources whose lead time
+// before runEnd has come, along with those already woken ahead during the run
+// that started at runStart, and returns them. It also returns the next time a
+// resource left in resourceManager is due to be woken ahead, or zero.
+func splitWakeAhead(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, runStart, runEnd, now time.Time) (*adapter.ResourceManager, time.Time) {
+	var next time.Time
+	early := resourceManager.Split(func(resource snoozetypes.SnoozableResource) bool {
+		if record := findWakeDuration(snoozeWindow, resource); !resource.IsSnoozed() && record != nil &&
+			record.WokenAt != nil && record.WokenAt.After(runStart) {
+			return true
+		}
+
+		wakeAt := runEnd.Add(-wakeLeadTime(snoozeWindow, resource))
+		if !wakeAt.After(now) {
+			return true
+		}
+		if wakeAt.Before(runEnd) && (next.IsZero() || wakeAt.Before(next)) {
+			next = wakeAt
+		}
+		return false
+	})
+	return early, next
+}
+
+// wakeAhead wakes the resources split off by splitWakeAhead and checks on them
+// until they are available. It returns true while it should come back, because
+// a tier or the wake rate held some of them back or they are not available yet.
+func (r *SnoozeWindowReconciler) wakeAhead(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, early *adapter.ResourceManager, now time.Time) (bool, error) {
+	if len(early.Resources()) == 0 {
+		return false, nil
+	}
+
+	if !snoozeWindow.Spec.DryRun && slices.ContainsFunc(early.Resources(), snoozetypes.SnoozableResource.IsSnoozed) {
+		logf.FromContext(ctx).Info("Waking resources ahead of the end of the run", "count", len(early.Resources()))
+		r.startWakeVerification(snoozeWindow, now)
+	}
+	waiting, err := early.WakeTiers(ctx, r.Client)
+	if err != nil {
+		return false, err
+	}
+	recordWokenAt(snoozeWindow, early.Woken(), now)
+	if waiting != nil || early.ThrottledFor() > 0 {
+		return true, nil
+	}
+	return r.verifyWake(ctx, snoozeWindow, early, now)
+}
+
+// wakeLeadTime returns how long before the end of a run resource is woken.
+func wakeLeadTime(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resource snoozetypes.SnoozableResource) time.Duration {
+	var lead time.Duration
+	if snoozeWindow.Spec.WakeLeadTime != nil {
+		lead = snoozeWindow.Spec.WakeLeadTime.Duration
+	}
+	if snoozeWindow.Spec.AutoTuneWakeLeadTime {
+		if record := findWakeDuration(snoozeWindow, resource); record != nil && record.Duration != nil {
+			lead = min(record.Duration.Duration*5/4, maxWakeLeadTime)
+		}
+	}
+	return lead
+}
+
+// recordWokenAt records the time the resources were woken in the window's
+// status, for their wake durations to be measured once they are available.
+func recordWokenAt(snoozeWindow *schedulingv1alpha1.SnoozeWindow, woken []snoozetypes.SnoozableResource, now time.Time) {
+	for _, resource := range woken {
+		if _, ok := resource.(snoozetypes.ReplicatedResource); !ok {
+			continue
+		}
+		record := findWakeDuration(snoozeWindow, resource)
+		if record == nil {
+			snoozeWindow.Status.WakeDurations = append(snoozeWindow.Status.WakeDurations, schedulingv1alpha1.WakeDuration{
+				Kind: resource.GetResourceType(),
+				Name: resource.GetName(),
+			})
+			record = &snoozeWindow.Status.WakeDurations[len(snoozeWindow.Status.WakeDurations)-1]
+		}
+		record.WokenAt = &metav1.Time{Time: now}
+	}
+}
+
+// wokenByLastWake reports whether the last wake of the window scaled resource
+// up.
+func wokenByLastWake(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resource snoozetypes.SnoozableResource) bool {
+	record := findWakeDuration(snoozeWindow, resource)
+	return record != nil && record.WokenAt != nil && snoozeWindow.Status.LastWakeTime != nil &&
+		!record.WokenAt.Before(snoozeWindow.Status.LastWakeTime)
+}
+
+// recordAvailable measures the wake duration of an available resource, once
+// for each time it was woken.
+func recordAvailable(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resource snoozetypes.SnoozableResource, now time.Time) {
+	record := findWakeDuration(snoozeWindow, resource)
+	if record == nil || record.WokenAt == nil || (record.AvailableAt != nil && record.AvailableAt.After(record.WokenAt.Time)) {
+		return
+	}
+
+	sample := now.Sub(record.WokenAt.Time)
+	if record.Duration != nil {
+		sample = (record.Duration.Duration + sample) / 2
+	}
+	record.AvailableAt = &metav1.Time{Time: now}
+	record.Duration = &metav1.Duration{Duration: sample.Round(time.Second)}
+}
+
+// pruneWakeDurations drops the wake durations of workloads the window no
+// longer selects.
+func pruneWakeDurations(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager) {
+	snoozeWindow.Status.WakeDurations = slices.DeleteFunc(snoozeWindow.Status.WakeDurations, func(record schedulingv1alpha1.WakeDuration) bool {
+		return !slices.ContainsFunc(resourceManager.Resources(), func(resource snoozetypes.SnoozableResource) bool {
+			return resource.GetResourceType() == record.Kind && resource.GetName() == record.Name
+		})
+	})
+}
+
+func findWakeDuration(snoozeWindow *schedulingv1alpha1.SnoozeWindow, resource snoozetypes.SnoozableResource) *schedulingv1alpha1.WakeDuration {
+	for i := range snoozeWindow.Status.WakeDurations {
+		record := &snoozeWindow.Status.WakeDurations[i]
+		if record.Kind == resource.GetResourceType() && record.Name == resource.GetName() {
+			return record
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+var _ = Describe("Wake lead time", func() {
+	var (
+		runStart, runEnd time.Time
+		snoozeWindow     *schedulingv1alpha1.SnoozeWindow
+		api, worker      snoozetypes.SnoozableResource
+	)
+
+	BeforeEach(func() {
+		runStart = time.Date(2026, 10, 19, 19, 0, 0, 0, time.UTC)
+		runEnd = time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				WakeLeadTime:         &metav1.Duration{Duration: 5 * time.Minute},
+				AutoTuneWakeLeadTime: true,
+			},
+		}
+		deployment := func(name string) snoozetypes.SnoozableResource {
+			return workloads.NewDeploymentAdapter(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+					Annotations: map[string]string{workloads.BackupReplicasKey: "2"}},
+				Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+			})
+		}
+		api, worker = deployment("api"), deployment("worker")
+	})
+
+	It("Should tune the lead time of a workload from its previous wakes", func() {
+		woken := runEnd.Add(-time.Hour)
+		recordWokenAt(snoozeWindow, []snoozetypes.SnoozableResource{api}, woken)
+		recordAvailable(snoozeWindow, api, woken.Add(8*time.Minute))
+		recordAvailable(snoozeWindow, api, woken.Add(20*time.Minute))
+
+		Expect(snoozeWindow.Status.WakeDurations).To(HaveLen(1))
+		Expect(snoozeWindow.Status.WakeDurations[0].Duration.Duration).To(Equal(8 * time.Minute))
+		Expect(wakeLeadTime(snoozeWindow, api)).To(Equal(10 * time.Minute))
+		Expect(wakeLeadTime(snoozeWindow, worker)).To(Equal(5 * time.Minute))
+
+		recordWokenAt(snoozeWindow, []snoozetypes.SnoozableResource{api}, woken.Add(24*time.Hour))
+		recordAvailable(snoozeWindow, api, woken.Add(24*time.Hour+4*time.Minute))
+		Expect(snoozeWindow.Status.WakeDurations[0].Duration.Duration).To(Equal(6 * time.Minute))
+	})
+
+	It("Should split off the resources whose lead time has come", func() {
+		snoozeWindow.Status.WakeDurations = []schedulingv1alpha1.WakeDuration{{
+			Kind: "deployment", Name: "api", Duration: &metav1.Duration{Duration: 16 * time.Minute},
+		}}
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.AddResource(api)
+		resourceManager.AddResource(worker)
+
+		early, next := splitWakeAhead(snoozeWindow, resourceManager, runStart, runEnd, runEnd.Add(-15*time.Minute))
+		Expect(early.Resources()).To(ConsistOf(api))
+		Expect(resourceManager.Resources()).To(ConsistOf(worker))
+		Expect(next).To(Equal(runEnd.Add(-5 * time.Minute)))
+	})
+})
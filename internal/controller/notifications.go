@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/notify"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+// notificationTimeout bounds the delivery of a message, retries included.
+const notificationTimeout = 2 * time.Minute
+
+// windowNotifier posts the transitions of a SnoozeWindow to its webhook.
+type windowNotifier struct {
+	r            *SnoozeWindowReconciler
+	snoozeWindow *schedulingv1alpha1.SnoozeWindow
+}
+
+// notifierFor returns the notifier of snoozeWindow, or nil when it does not
+// set notifications.
+func (r *SnoozeWindowReconciler) notifierFor(snoozeWindow *schedulingv1alpha1.SnoozeWindow) adapter.Notifier {
+	if snoozeWindow.Spec.Notifications == nil {
+		return nil
+	}
+	return &windowNotifier{r: r, snoozeWindow: snoozeWindow}
+}
+
+// Notify sends the message of a finished or failed transition.
+func (n *windowNotifier) Notify(ctx context.Context, action string, resources []snoozetypes.SnoozableResource, err error) {
+	message := notify.Message{
+		Window:    n.snoozeWindow.Name,
+		Namespace: n.snoozeWindow.Namespace,
+		Action:    action,
+	}
+	switch {
+	case err != nil:
+		message.Event = string(schedulingv1alpha1.NotificationEventFailed)
+		message.Error = err.Error()
+	case action == schedulingv1alpha1.ActionSnooze:
+		message.Event = string(schedulingv1alpha1.NotificationEventSnoozed)
+	default:
+		message.Event = string(schedulingv1alpha1.NotificationEventWoken)
+	}
+	message.Resources = resourceNames(resources)
+	n.send(ctx, message)
+}
+
+// send renders message and sends it in the background, as retries back off
+// for a while, unless the window leaves its event out. A message that cannot
+// be delivered is reported with a NotificationFailed Event.
+func (n *windowNotifier) send(ctx context.Context, message notify.Message) {
+	logger := logf.FromContext(ctx)
+	spec := n.snoozeWindow.Spec.Notifications
+	if len(spec.Events) > 0 && !slices.Contains(spec.Events, schedulingv1alpha1.NotificationEvent(message.Event)) {
+		return
+	}
+
+	// The Event is recorded from the background, while the reconcile goes on
+	// updating the window
+	snoozeWindow := n.snoozeWindow.DeepCopy()
+	text, err := notify.Render(spec.Template, message)
+	if err != nil {
+		n.r.Recorder.Eventf(snoozeWindow, corev1.EventTypeWarning, "NotificationFailed", "Failed to render notification: %s", err)
+		return
+	}
+	webhook, err := n.webhook(ctx)
+	if err != nil {
+		n.r.Recorder.Eventf(snoozeWindow, corev1.EventTypeWarning, "NotificationFailed", "Failed to resolve notification webhook: %s", err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationTimeout)
+		defer cancel()
+		if err := webhook.Send(ctx, text); err != nil {
+			logger.Error(err, "Failed to send notification", "event", message.Event)
+			n.r.Recorder.Eventf(snoozeWindow, corev1.EventTypeWarning, "NotificationFailed",
+				"Failed to send %s notification: %s", message.Event, err)
+			return
+		}
+		logger.Info("Sent notification", "event", message.Event)
+	}()
+}
+
+func resourceNames(resources []snoozetypes.SnoozableResource) []string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.GetResourceType()+"/"+resource.GetName())
+	}
+	return names
+}
+
+// webhook returns the webhook of the window's notifications, with the URL and
+// token of its secret. The URL has to be on one of the reconciler's
+// NotificationHosts.
+func (n *windowNotifier) webhook(ctx context.Context) (*notify.Webhook, error) {
+	spec := n.snoozeWindow.Spec.Notifications
+	webhook := &notify.Webhook{
+		URL:     spec.URL,
+		Retries: int(ptr.Deref(spec.Retries, 3)),
+	}
+
+	if spec.SecretRef != nil {
+		// Secrets are read uncached, so the manager does not watch every Secret
+		var secret corev1.Secret
+		if err := n.r.APIReader.Get(ctx, types.NamespacedName{Name: spec.SecretRef.Name, Namespace: n.snoozeWindow.Namespace}, &secret); err != nil {
+			return nil, err
+		}
+		if url := string(secret.Data["url"]); url != "" {
+			webhook.URL = url
+		}
+		webhook.Token = string(secret.Data["token"])
+	}
+
+	if webhook.URL == "" {
+		if spec.SecretRef != nil {
+			return nil, fmt.Errorf("secret %s has no url key and spec.notifications.url is not set", spec.SecretRef.Name)
+		}
+		return nil, errors.New("spec.notifications.url is not set")
+	}
+	// Windows are namespaced, so their URLs are kept to the hosts the operator allows
+	if err := notify.CheckURL(webhook.URL, n.r.NotificationHosts); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
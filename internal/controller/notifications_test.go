@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Notifications", func() {
+	var (
+		ctx          context.Context
+		mu           sync.Mutex
+		texts        []string
+		tokens       []string
+		server       *httptest.Server
+		deployment   *appsv1.Deployment
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+		reconciler   *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		texts, tokens = nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Text string `json:"text"`
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			mu.Lock()
+			defer mu.Unlock()
+			texts = append(texts, body.Text)
+			tokens = append(tokens, r.Header.Get("Authorization"))
+		}))
+		DeferCleanup(server.Close)
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "chat", Namespace: "default"},
+			Data:       map[string][]byte{"url": []byte(server.URL), "token": []byte("s3cret")},
+		}
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				Notifications: &schedulingv1alpha1.NotificationSpec{
+					SecretRef: &corev1.LocalObjectReference{Name: "chat"},
+					Template:  "{{.Window}} {{.Event}} {{join .Resources \",\"}}",
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, secret).Build()
+		reconciler = &SnoozeWindowReconciler{
+			Client:            c,
+			Recorder:          record.NewFakeRecorder(10),
+			APIReader:         c,
+			NotificationHosts: []string{"127.0.0.1"},
+		}
+	})
+
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(texts)
+	}
+
+	It("Should post a finished snooze and wake to the webhook of the secret", func() {
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.Owner = snoozeWindow.Name
+		resourceManager.Notifier = reconciler.notifierFor(snoozeWindow)
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(deployment))
+
+		Expect(resourceManager.SnoozeAll(ctx, reconciler.Client)).To(Succeed())
+		Eventually(received).Should(Equal([]string{"nightly Snoozed deployment/api"}))
+
+		// Snoozing again changes nothing and is not notified
+		Expect(resourceManager.SnoozeAll(ctx, reconciler.Client)).To(Succeed())
+		Expect(resourceManager.WakeAll(ctx, reconciler.Client)).To(Succeed())
+		Eventually(received).Should(Equal([]string{"nightly Snoozed deployment/api", "nightly Woken deployment/api"}))
+		Expect(tokens).To(HaveEach("Bearer s3cret"))
+	})
+
+	It("Should only notify the events it is asked to", func() {
+		snoozeWindow.Spec.Notifications.Events = []schedulingv1alpha1.NotificationEvent{schedulingv1alpha1.NotificationEventFailed}
+		notifier := reconciler.notifierFor(snoozeWindow)
+
+		notifier.Notify(ctx, schedulingv1alpha1.ActionWake, nil, nil)
+		notifier.Notify(ctx, schedulingv1alpha1.ActionWake, nil, errors.New("forbidden"))
+		Eventually(received).Should(Equal([]string{"nightly Failed "}))
+		Consistently(received).Should(HaveLen(1))
+	})
+
+	It("Should not post to a host the operator does not allow", func() {
+		reconciler.NotificationHosts = []string{"hooks.slack.com"}
+		reconciler.notifierFor(snoozeWindow).Notify(ctx, schedulingv1alpha1.ActionSnooze, nil, nil)
+
+		recorder := reconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(`NotificationFailed Failed to resolve notification webhook: webhook host "127.0.0.1" is not among the allowed notification hosts`)))
+		Consistently(received).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/metrics"
+)
+
+// Keys of the pricing ConfigMap.
+const (
+	PricePerCoreHourKey = "pricePerCoreHour"
+	PricePerGiBHourKey  = "pricePerGiBHour"
+)
+
+const bytesPerGiB = 1 << 30
+
+// pricing is the price of a core hour and of a GiB hour of memory.
+type pricing struct {
+	perCoreHour float64
+	perGiBHour  float64
+}
+
+// reconcileSavings adds what the replicas snoozed since the last update would
+// have requested to the window's totals, and records what is snoozed now. The
+// totals are only brought up to date while something is snoozed, so an idle
+// window does not rewrite its status on every reconcile.
+func (r *SnoozeWindowReconciler) reconcileSavings(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) {
+	logger := logf.FromContext(ctx)
+	now = now.Truncate(time.Second)
+
+	requests := resourceManager.SnoozedRequests()
+	cpu, memory := requests.Cpu(), requests.Memory()
+
+	savings := snoozeWindow.Status.Savings
+	if savings == nil {
+		if cpu.IsZero() && memory.IsZero() {
+			return
+		}
+		savings = &schedulingv1alpha1.SavingsStatus{}
+		snoozeWindow.Status.Savings = savings
+	}
+
+	coreHours := parseTotal(savings.CPUCoreHours)
+	gibHours := parseTotal(savings.MemoryGiBHours)
+	snoozing := !savings.SnoozedCPU.IsZero() || !savings.SnoozedMemory.IsZero()
+	if snoozing && savings.LastUpdateTime != nil {
+		// What was snoozed at the last update stayed snoozed until now
+		hours := now.Sub(savings.LastUpdateTime.Time).Hours()
+		coreHours += savings.SnoozedCPU.AsApproximateFloat64() * hours
+		gibHours += savings.SnoozedMemory.AsApproximateFloat64() / bytesPerGiB * hours
+		savings.CPUCoreHours = formatTotal(coreHours)
+		savings.MemoryGiBHours = formatTotal(gibHours)
+		savings.LastUpdateTime = &metav1.Time{Time: now}
+	}
+	if !cpu.Equal(savings.SnoozedCPU) || !memory.Equal(savings.SnoozedMemory) {
+		savings.SnoozedCPU = *cpu
+		savings.SnoozedMemory = *memory
+		savings.LastUpdateTime = &metav1.Time{Time: now}
+	}
+
+	metrics.CPUCoreHoursSaved.WithLabelValues(snoozeWindow.Name, snoozeWindow.Namespace).Set(coreHours)
+	metrics.MemoryGiBHoursSaved.WithLabelValues(snoozeWindow.Name, snoozeWindow.Namespace).Set(gibHours)
+
+	prices, err := r.getPricing(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to read pricing", "configMap", r.PricingConfigMap)
+		return
+	}
+	if prices == nil {
+		return
+	}
+	cost := coreHours*prices.perCoreHour + gibHours*prices.perGiBHour
+	savings.EstimatedCost = strconv.FormatFloat(cost, 'f', 2, 64)
+	metrics.EstimatedCostSaved.WithLabelValues(snoozeWindow.Name, snoozeWindow.Namespace).Set(cost)
+}
+
+// getPricing reads the pricing ConfigMap. It returns nil when no ConfigMap is
+// configured.
+func (r *SnoozeWindowReconciler) getPricing(ctx context.Context) (*pricing, error) {
+	if r.PricingConfigMap == (types.NamespacedName{}) {
+		return nil, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, r.PricingConfigMap, &configMap); err != nil {
+		return nil, err
+	}
+
+	prices := &pricing{}
+	for key, price := range map[string]*float64{
+		PricePerCoreHourKey: &prices.perCoreHour,
+		PricePerGiBHourKey:  &prices.perGiBHour,
+	} {
+		value, exists := configMap.Data[key]
+		if !exists {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		*price = parsed
+	}
+	return prices, nil
+}
+
+func parseTotal(total string) float64 {
+	parsed, err := strconv.ParseFloat(total, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+func formatTotal(total float64) string {
+	return strconv.FormatFloat(total, 'f', 4, 64)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Savings", func() {
+	var (
+		ctx             context.Context
+		now             time.Time
+		snoozeWindow    *schedulingv1alpha1.SnoozeWindow
+		resourceManager *adapter.ResourceManager
+		reconciler      *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now().Truncate(time.Second)
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		}
+
+		// Three snoozed replicas requesting 500m and 1Gi each
+		resourceManager = adapter.NewResourceManager()
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api",
+				Namespace:   "default",
+				Annotations: map[string]string{workloads.BackupReplicasKey: "3"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](0),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "api",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					}},
+				}}}},
+			},
+		}))
+
+		pricing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "pricing", Namespace: "kube-snooze-system"},
+			Data:       map[string]string{PricePerCoreHourKey: "0.04", PricePerGiBHourKey: "0.005"},
+		}
+		reconciler = &SnoozeWindowReconciler{
+			Client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pricing).Build(),
+			Recorder:         record.NewFakeRecorder(10),
+			PricingConfigMap: types.NamespacedName{Name: "pricing", Namespace: "kube-snooze-system"},
+		}
+	})
+
+	It("Should record what is snoozed without adding to the totals", func() {
+		reconciler.reconcileSavings(ctx, snoozeWindow, resourceManager, now)
+
+		savings := snoozeWindow.Status.Savings
+		Expect(savings).NotTo(BeNil())
+		Expect(savings.SnoozedCPU.String()).To(Equal("1500m"))
+		Expect(savings.SnoozedMemory.String()).To(Equal("3Gi"))
+		Expect(savings.CPUCoreHours).To(BeEmpty())
+		Expect(savings.LastUpdateTime.Time).To(Equal(now))
+	})
+
+	It("Should add what stayed snoozed since the last update and price it", func() {
+		snoozeWindow.Status.Savings = &schedulingv1alpha1.SavingsStatus{
+			SnoozedCPU:     resource.MustParse("1500m"),
+			SnoozedMemory:  resource.MustParse("3Gi"),
+			CPUCoreHours:   "1.0000",
+			MemoryGiBHours: "2.0000",
+			LastUpdateTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+		}
+
+		reconciler.reconcileSavings(ctx, snoozeWindow, resourceManager, now)
+
+		savings := snoozeWindow.Status.Savings
+		Expect(savings.CPUCoreHours).To(Equal("4.0000"))
+		Expect(savings.MemoryGiBHours).To(Equal("8.0000"))
+		Expect(savings.EstimatedCost).To(Equal("0.20"))
+		Expect(savings.LastUpdateTime.Time).To(Equal(now))
+	})
+
+	It("Should leave an idle window without savings untouched", func() {
+		reconciler.reconcileSavings(ctx, snoozeWindow, adapter.NewResourceManager(), now)
+
+		Expect(snoozeWindow.Status.Savings).To(BeNil())
+	})
+})
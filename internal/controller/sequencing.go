@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+)
+
+// Reasons of the Sequencing condition.
+const (
+	reasonWaitingToSnooze = "WaitingToSnooze"
+	reasonWaitingToWake   = "WaitingToWake"
+	reasonTiersSettled    = "TiersSettled"
+	reasonWakeThrottled   = "WakeThrottled"
+)
+
+// setSequencingCondition reports the tier a snooze or wake of the window is
+// waiting on, or that none is, and announces each new tier waited on with an
+// Event.
+func (r *SnoozeWindowReconciler) setSequencingCondition(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, action string, waiting *adapter.Tier) {
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionSequencing,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonTiersSettled,
+		Message:            "No tier of resources is waited on",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if waiting == nil {
+		meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+		return
+	}
+
+	pending := waiting.Pending(action == schedulingv1alpha1.ActionSnooze)
+	names := make([]string, 0, len(pending))
+	for _, resource := range pending {
+		names = append(names, resource.GetResourceType()+"/"+resource.GetName())
+	}
+	logf.FromContext(ctx).Info("Waiting for tier to settle", "action", action, "tier", waiting.Name, "pending", names)
+
+	condition.Status = metav1.ConditionTrue
+	if action == schedulingv1alpha1.ActionSnooze {
+		condition.Reason = reasonWaitingToSnooze
+		condition.Message = fmt.Sprintf("Waiting for tier %q to scale down before snoozing the tier before it", waiting.Name)
+	} else {
+		condition.Reason = reasonWaitingToWake
+		condition.Message = fmt.Sprintf("Waiting for tier %q to be ready before waking the next tier", waiting.Name)
+	}
+
+	previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSequencing)
+	if previous == nil || previous.Message != condition.Message {
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeNormal, "WaitingForTier",
+			condition.Message+": "+strings.Join(names, ", "))
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+}
+
+// setThrottledCondition reports a wake held back by the wake rate limits.
+func (r *SnoozeWindowReconciler) setThrottledCondition(snoozeWindow *schedulingv1alpha1.SnoozeWindow, delay time.Duration) {
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionSequencing,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonWakeThrottled,
+		Message:            "Waking at the configured wake rate, the next resource is woken in " + delay.Round(time.Second).String(),
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+}
+
+// isWaitingToWake reports whether a wake of the window stopped at a tier that
+// was not ready yet, or was held back by the wake rate limits.
+func isWaitingToWake(snoozeWindow *schedulingv1alpha1.SnoozeWindow) bool {
+	condition := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSequencing)
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		(condition.Reason == reasonWaitingToWake || condition.Reason == reasonWakeThrottled)
+}
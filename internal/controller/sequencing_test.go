@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+var _ = Describe("Sequencing", func() {
+	snoozed := func(annotations map[string]string) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Namespace: "default", Annotations: map[string]string{workloads.BackupReplicasKey: "1"}}
+		for key, value := range annotations {
+			meta.Annotations[key] = value
+		}
+		return meta
+	}
+
+	var (
+		ctx             context.Context
+		c               client.Client
+		postgres        *appsv1.StatefulSet
+		api             *appsv1.Deployment
+		resourceManager *adapter.ResourceManager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		postgresMeta := snoozed(nil)
+		postgresMeta.Name = "postgres"
+		postgres = &appsv1.StatefulSet{ObjectMeta: postgresMeta, Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](0)}}
+		apiMeta := snoozed(nil)
+		apiMeta.Name = "api"
+		api = &appsv1.Deployment{ObjectMeta: apiMeta, Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)}}
+		workerMeta := snoozed(map[string]string{schedulingv1alpha1.DependsOnAnnotation: "api"})
+		workerMeta.Name = "worker"
+		worker := &appsv1.Deployment{ObjectMeta: workerMeta, Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)}}
+		c = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(postgres, api, worker).Build()
+
+		resourceManager = adapter.NewResourceManager()
+		resourceManager.Tiers = []schedulingv1alpha1.SnoozeTier{
+			{Name: "databases", ResourceTypes: []string{schedulingv1alpha1.ResourceTypeStatefulSet}},
+		}
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(worker))
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(api))
+		resourceManager.AddResource(workloads.NewStatefulSetAdapter(postgres))
+	})
+
+	It("Should wake the tiers in order and wait for each to be ready", func() {
+		var names [][]string
+		for _, tier := range resourceManager.Sequence() {
+			var resources []string
+			for _, resource := range tier.Resources {
+				resources = append(resources, resource.GetName())
+			}
+			names = append(names, append([]string{tier.Name}, resources...))
+		}
+		Expect(names).To(Equal([][]string{{"databases", "postgres"}, {"1", "api"}, {"2", "worker"}}))
+
+		waiting, err := resourceManager.WakeTiers(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waiting).NotTo(BeNil())
+		Expect(waiting.Name).To(Equal("databases"))
+
+		stored := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(api), stored)).To(Succeed())
+		Expect(stored.Spec.Replicas).To(HaveValue(Equal(int32(0))))
+		Expect(stored.Annotations).To(HaveKey(workloads.BackupReplicasKey))
+	})
+
+	It("Should wake the next tier once the wake deadline has passed", func() {
+		resourceManager.WakeDeadline = time.Now().Add(-time.Minute)
+
+		waiting, err := resourceManager.WakeTiers(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waiting).To(BeNil())
+		Expect(resourceManager.OverdueTiers()).To(ConsistOf("databases", "1"))
+
+		stored := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(api), stored)).To(Succeed())
+		Expect(stored.Annotations).NotTo(HaveKey(workloads.BackupReplicasKey))
+	})
+
+	It("Should not wait on awake resources the wake did not scale up", func() {
+		// postgres was never snoozed, and is not ready
+		awake := postgres.DeepCopy()
+		awake.Annotations = nil
+		awake.Spec.Replicas = ptr.To[int32](1)
+		Expect(c.Update(ctx, awake)).To(Succeed())
+		resourceManager = adapter.NewResourceManager()
+		resourceManager.Tiers = []schedulingv1alpha1.SnoozeTier{
+			{Name: "databases", ResourceTypes: []string{schedulingv1alpha1.ResourceTypeStatefulSet}},
+		}
+		resourceManager.AddResource(workloads.NewStatefulSetAdapter(awake))
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(api))
+		resourceManager.WokenByWake = func(snoozetypes.SnoozableResource) bool { return false }
+
+		waiting, err := resourceManager.WakeTiers(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waiting).To(BeNil())
+
+		stored := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(api), stored)).To(Succeed())
+		Expect(stored.Annotations).NotTo(HaveKey(workloads.BackupReplicasKey))
+	})
+})
@@ -18,35 +18,91 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
 	"codeacme.org/kube-snooze/internal/controller/adapter"
-	"codeacme.org/kube-snooze/internal/controller/adapter/jobs"
-	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/metrics"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
 	"codeacme.org/kube-snooze/internal/utils"
 )
 
+const (
+	// passedLookback is how far back a run of the schedule wakes the window's
+	// resources once it has ended, enough to cover a weekly schedule.
+	passedLookback = 8 * 24 * time.Hour
+	// previewTransitions is the number of upcoming transitions kept in status.
+	previewTransitions = 4
+	// pollInterval is how often a window waiting for a tier to settle, or for
+	// woken workloads to become available, checks on them again. Status changes
+	// of workloads do not trigger a reconcile.
+	pollInterval = 10 * time.Second
+	// noRequeue stands for a snooze without an end, which is not requeued.
+	noRequeue = time.Duration(math.MaxInt64)
+)
+
 // SnoozeWindowReconciler reconciles a SnoozeWindow object
 type SnoozeWindowReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader reads the objects that are not cached, such as the Secrets of
+	// notification webhooks, straight from the API server.
+	APIReader client.Reader
+
+	// PricingConfigMap holds the prices used to estimate the cost saved by each
+	// window. Costs are not estimated when it is empty.
+	PricingConfigMap types.NamespacedName
+
+	// WakeLimits paces wakes across windows. Wakes are not paced when it is nil.
+	WakeLimits *WakeLimits
+
+	// PrometheusURL is the Prometheus server queried for the activity of
+	// windows snoozing idle workloads with the Prometheus source.
+	PrometheusURL string
+
+	// NotificationHosts are the hosts windows may send notifications to. A
+	// host starting with "*." allows the hosts of that domain.
+	NotificationHosts []string
 }
 
 // +kubebuilder:rbac:groups=scheduling.codeacme.org,resources=snoozewindows,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=scheduling.codeacme.org,resources=snoozewindows/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=scheduling.codeacme.org,resources=snoozewindows/finalizers,verbs=update
+// +kubebuilder:rbac:groups=scheduling.codeacme.org,resources=snoozecalendars,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods;configmaps,verbs=get;list;watch;update;patch;create;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
 
 func (r *SnoozeWindowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
@@ -56,92 +112,632 @@ func (r *SnoozeWindowReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	snoozeWindow := &schedulingv1alpha1.SnoozeWindow{}
 	if err := r.Get(ctx, req.NamespacedName, snoozeWindow); err != nil {
 		if errors.IsNotFound(err) {
+			metrics.ForgetWindow(req.Name, req.Namespace)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get SnoozeWindow")
 		return ctrl.Result{}, err
 	}
 	logger.Info("Reconciling SnoozeWindow", "name", snoozeWindow.Name, "namespace", snoozeWindow.Namespace)
-	labelSelectors := snoozeWindow.Spec.LabelSelector
+	original := snoozeWindow.DeepCopy()
+	now := time.Now()
 
-	isSnoozeActive, hasWindowPassed, duration, err := utils.IsTimeOngoing(snoozeWindow.Spec.SnoozeSchedule.StartTime, snoozeWindow.Spec.SnoozeSchedule.EndTime, snoozeWindow.Spec.SnoozeSchedule.Date)
+	var isSnoozeActive, hasWindowPassed bool
+	var snoozeEnd time.Time
+	var duration time.Duration
+	schedule, err := utils.ScheduleFor(snoozeWindow)
 	if err != nil {
 		logger.Error(err, "parsing snooze schedule")
+	} else if err := utils.ApplyCalendars(ctx, r.Client, snoozeWindow, schedule); err != nil {
+		// Following the schedule without its holidays or skipped dates could
+		// snooze on a release weekend, so nothing is done until the calendars load
+		logger.Error(err, "Failed to load calendars")
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "CalendarFailed", err.Error())
+		return ctrl.Result{}, err
+	} else {
+		isSnoozeActive, snoozeEnd = schedule.Active(now)
+		hasWindowPassed = schedule.Passed(now, passedLookback)
+		duration = snoozeEnd.Sub(now)
 	}
+	snoozeWindow.Status.NextTransitions = nextTransitions(schedule, now)
 
-	// TODO: Decouple Resource Finder from buildResourceManager
-	resourceManager, err := r.buildResourceManager(ctx, snoozeWindow.Namespace, labelSelectors)
+	resourceManager, err := adapter.ForWindow(ctx, r.Client, snoozeWindow)
 	if err != nil {
 		logger.Error(err, "failed to build resource manager")
 		return ctrl.Result{}, err
 	}
+	resourceManager.DryRun = snoozeWindow.Spec.DryRun
+	resourceManager.WakeLimiter = r.WakeLimits.limiterFor(snoozeWindow)
+	resourceManager.Notifier = r.notifierFor(snoozeWindow)
+	// A run that ended within the lookback is only woken from while resources
+	// the window snoozed are still asleep, not on every reconcile after it
+	hasWindowPassed = hasWindowPassed && len(resourceManager.Owned()) > 0
+
+	if snoozeWindow.Spec.Suspended {
+		if err := r.resolveConflicts(ctx, snoozeWindow, resourceManager, intentNone, now); err != nil {
+			logger.Error(err, "Failed to resolve conflicts with other windows")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileSuspended(ctx, snoozeWindow, resourceManager); err != nil {
+			logger.Error(err, "failed to wake resources of suspended window")
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileSnoozeWarning(ctx, snoozeWindow, resourceManager, time.Time{}); err != nil {
+			logger.Error(err, "Failed to warn of the upcoming snooze")
+			return ctrl.Result{}, err
+		}
+		r.recordOutcome(ctx, snoozeWindow, resourceManager, now)
+		// Nothing to do until the window is resumed, which triggers a reconcile
+		if err := r.updateStatus(ctx, original, snoozeWindow); err != nil {
+			logger.Error(err, "failed to update SnoozeWindow status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSuspended) {
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeNormal, "Resumed", "SnoozeWindow resumed, following its schedule again")
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionSuspended,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Active",
+		Message:            "SnoozeWindow follows its schedule",
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+
+	windowOverride, nextOverrideExpiry := r.reconcileWakeOverrides(ctx, snoozeWindow, resourceManager, now)
+	manualSnoozeUntil, manualSnoozeEnded := r.reconcileManualSnooze(ctx, snoozeWindow, now)
+	manualSnooze := !manualSnoozeUntil.IsZero()
+	idleSnooze, idleSnoozeEnded, nextSample := r.reconcileIdleSnooze(ctx, snoozeWindow, resourceManager, isSnoozeActive || manualSnooze, windowOverride, now)
+
+	self := intentNone
+	switch {
+	case windowOverride:
+		self = intentAwake
+	case isSnoozeActive || manualSnooze || idleSnooze:
+		self = intentAsleep
+	}
+	if err := r.resolveConflicts(ctx, snoozeWindow, resourceManager, self, now); err != nil {
+		logger.Error(err, "Failed to resolve conflicts with other windows")
+		return ctrl.Result{}, err
+	}
+
+	var result ctrl.Result
+	if (isSnoozeActive || manualSnooze || idleSnooze) && !windowOverride {
+		// Resources whose wake lead time has come are woken ahead of the end of the run
+		early, nextWakeAhead := adapter.NewResourceManager(), time.Time{}
+		if isSnoozeActive && !manualSnooze && !idleSnooze {
+			if run, ok := schedule.Next(now); ok {
+				early, nextWakeAhead = splitWakeAhead(snoozeWindow, resourceManager, run.Start, snoozeEnd, now)
+			}
+		}
+
+		// The pre-snooze hooks run once per snooze, which is told apart by its end,
+		// or by its start for an idle snooze
+		var hookKey time.Time
+		switch {
+		case isSnoozeActive:
+			hookKey = snoozeEnd
+		case manualSnooze:
+			hookKey = manualSnoozeUntil
+		default:
+			hookKey = snoozeWindow.Status.IdleSnoozeTime.Time
+		}
+		hooks, err := r.runHooks(ctx, snoozeWindow, hookPhasePreSnooze, snoozeWindow.Spec.PreSnoozeHooks, hookKey)
+		if err != nil {
+			logger.Error(err, "Failed to run pre-snooze hooks")
+			return ctrl.Result{}, err
+		}
+
+		var waiting *adapter.Tier
+		if hooks == hooksSucceeded {
+			if waiting, err = resourceManager.SnoozeTiers(ctx, r.Client); err != nil {
+				logger.Error(err, "failed to snooze resources")
+				return ctrl.Result{}, err
+			}
+			r.setSequencingCondition(ctx, snoozeWindow, schedulingv1alpha1.ActionSnooze, waiting)
+		}
+		if err := r.reconcileSnoozeWarning(ctx, snoozeWindow, resourceManager, time.Time{}); err != nil {
+			logger.Error(err, "Failed to warn of the upcoming snooze")
+			return ctrl.Result{}, err
+		}
 
-	if isSnoozeActive {
-		if err := resourceManager.SnoozeAll(ctx, r.Client); err != nil {
-			logger.Error(err, "failed to snooze resources")
+		wakingAhead, err := r.wakeAhead(ctx, snoozeWindow, early, now)
+		if err != nil {
+			logger.Error(err, "Failed to wake resources ahead of the end of the run")
 			return ctrl.Result{}, err
 		}
 
-		logger.Info("RequeingScheduler", "interval", duration)
-		return ctrl.Result{RequeueAfter: duration}, nil
+		// Resources stay snoozed until both the schedule and an on-demand snooze
+		// end, and an idle snooze until a wake override, which triggers a reconcile
+		if idleSnooze {
+			duration = noRequeue
+		} else if !isSnoozeActive || (manualSnooze && manualSnoozeUntil.Sub(now) > duration) {
+			duration = manualSnoozeUntil.Sub(now)
+		}
+		// Come back when a resource's wake override ends to snooze it again
+		if !nextOverrideExpiry.IsZero() && nextOverrideExpiry.Sub(now) < duration {
+			duration = nextOverrideExpiry.Sub(now)
+		}
+
+		if !nextWakeAhead.IsZero() && nextWakeAhead.Sub(now) < duration {
+			duration = nextWakeAhead.Sub(now)
+		}
+		if (waiting != nil || wakingAhead || hooks == hooksRunning) && pollInterval < duration {
+			duration = pollInterval
+		}
+
+		if duration == noRequeue {
+			logger.Info("Snoozed until a wake override, not requeuing")
+		} else {
+			logger.Info("RequeingScheduler", "interval", duration)
+			result = ctrl.Result{RequeueAfter: duration}
+		}
 	} else {
-		if hasWindowPassed {
-			if err := resourceManager.WakeAll(ctx, r.Client); err != nil {
+		// A scheduled wake starts once the window's jitter offset has passed
+		continuing := isWaitingToWake(snoozeWindow)
+		var jitterUntil time.Time
+		if hasWindowPassed && !windowOverride && !manualSnoozeEnded && !idleSnoozeEnded && !continuing {
+			if lastWake, ok := schedule.LastWake(now, passedLookback); ok {
+				if wakeAt := lastWake.Add(r.WakeLimits.jitterFor(snoozeWindow)); now.Before(wakeAt) {
+					logger.Info("Delaying wake by the window's jitter", "until", wakeAt)
+					jitterUntil = wakeAt
+				}
+			}
+		}
+
+		// A wake waiting on a tier carries on even once the run it ended is out of sight
+		var waiting *adapter.Tier
+		if (hasWindowPassed || windowOverride || manualSnoozeEnded || idleSnoozeEnded || continuing) && jitterUntil.IsZero() {
+			if !continuing && !snoozeWindow.Spec.DryRun && slices.ContainsFunc(resourceManager.Resources(), snoozetypes.SnoozableResource.IsSnoozed) {
+				r.startWakeVerification(snoozeWindow, now)
+			}
+			// Tiers wait on the workloads this wake scaled up, until the wake timeout
+			resourceManager.WokenByWake = func(resource snoozetypes.SnoozableResource) bool {
+				return wokenByLastWake(snoozeWindow, resource)
+			}
+			if lastWake := snoozeWindow.Status.LastWakeTime; lastWake != nil {
+				resourceManager.WakeDeadline = lastWake.Add(wakeTimeout(snoozeWindow))
+			}
+			if waiting, err = resourceManager.WakeTiers(ctx, r.Client); err != nil {
 				logger.Error(err, "failed to wake resources")
 				return ctrl.Result{}, err
 			}
+			r.reportOverdueTiers(snoozeWindow, resourceManager.OverdueTiers())
+			recordWokenAt(snoozeWindow, resourceManager.Woken(), now)
+			pruneWakeDurations(snoozeWindow, resourceManager)
+		}
+		throttledFor := resourceManager.ThrottledFor()
+		if throttledFor > 0 {
+			r.setThrottledCondition(snoozeWindow, throttledFor)
+		} else {
+			r.setSequencingCondition(ctx, snoozeWindow, schedulingv1alpha1.ActionWake, waiting)
+		}
+
+		// The woken workloads are checked on once every tier is awake
+		var verifying bool
+		if waiting == nil && throttledFor == 0 {
+			if verifying, err = r.verifyWake(ctx, snoozeWindow, resourceManager, now); err != nil {
+				logger.Error(err, "Failed to verify woken workloads")
+				return ctrl.Result{}, err
+			}
+		}
+
+		// The post-wake hooks run once the woken workloads are available
+		var runningHooks bool
+		if waiting == nil && throttledFor == 0 && !verifying && snoozeWindow.Status.LastWakeTime != nil &&
+			meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeVerified) {
+			hooks, err := r.runHooks(ctx, snoozeWindow, hookPhasePostWake, snoozeWindow.Spec.PostWakeHooks, snoozeWindow.Status.LastWakeTime.Time)
+			if err != nil {
+				logger.Error(err, "Failed to run post-wake hooks")
+				return ctrl.Result{}, err
+			}
+			runningHooks = hooks == hooksRunning
+			if hooks == hooksFailed {
+				hooksCondition := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionLifecycleHooks)
+				meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+					Type:               schedulingv1alpha1.ConditionWakeDegraded,
+					Status:             metav1.ConditionTrue,
+					Reason:             "HookFailed",
+					Message:            hooksCondition.Message,
+					ObservedGeneration: snoozeWindow.Generation,
+				})
+			}
+		}
+
+		// Come back at the next transition of the schedule, or when the wake override ends
+		var requeueAt, snoozeAt time.Time
+		if isSnoozeActive {
+			requeueAt = snoozeEnd
+		} else if schedule != nil {
+			if next, ok := schedule.Next(now); ok {
+				requeueAt = next.Start
+				// The resources are warned about the snooze ahead of it
+				if warnAt, warns := snoozeWarningAt(snoozeWindow, next.Start); warns && now.Before(warnAt) {
+					requeueAt = warnAt
+				} else if warns && !windowOverride {
+					snoozeAt = next.Start
+				}
+			}
+		}
+		if err := r.reconcileSnoozeWarning(ctx, snoozeWindow, resourceManager, snoozeAt); err != nil {
+			logger.Error(err, "Failed to warn of the upcoming snooze")
+			return ctrl.Result{}, err
+		}
+		if until := snoozeWindow.Status.WakeOverrideUntil; until != nil && (requeueAt.IsZero() || until.Time.Before(requeueAt)) {
+			requeueAt = until.Time
+		}
+		if poll := now.Add(pollInterval); (waiting != nil || verifying || runningHooks) && (requeueAt.IsZero() || poll.Before(requeueAt)) {
+			requeueAt = poll
+		}
+		if throttledFor > 0 && (requeueAt.IsZero() || now.Add(throttledFor).Before(requeueAt)) {
+			requeueAt = now.Add(throttledFor)
+		}
+		if !jitterUntil.IsZero() && (requeueAt.IsZero() || jitterUntil.Before(requeueAt)) {
+			requeueAt = jitterUntil
+		}
+		// Idle workloads are measured again until they are snoozed
+		if !nextSample.IsZero() && (requeueAt.IsZero() || nextSample.Before(requeueAt)) {
+			requeueAt = nextSample
+		}
+
+		if requeueAt.IsZero() {
+			logger.Info("No upcoming transition, not requeuing")
+		} else {
+			logger.Info("RequeingScheduler", "interval", requeueAt.Sub(now))
+			result = ctrl.Result{RequeueAfter: requeueAt.Sub(now)}
+		}
+	}
+	r.recordOutcome(ctx, snoozeWindow, resourceManager, now)
+
+	if err := r.updateStatus(ctx, original, snoozeWindow); err != nil {
+		logger.Error(err, "failed to update SnoozeWindow status")
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// nextTransitions previews the next transitions of schedule for the window's
+// status, in UTC and in the schedule's timezone.
+func nextTransitions(schedule *utils.Schedule, now time.Time) []schedulingv1alpha1.ScheduledTransition {
+	if schedule == nil {
+		return nil
+	}
+
+	var transitions []schedulingv1alpha1.ScheduledTransition
+	for _, transition := range schedule.NextTransitions(now, previewTransitions) {
+		transitions = append(transitions, schedulingv1alpha1.ScheduledTransition{
+			Action:    transition.Action,
+			Time:      metav1.NewTime(transition.Time.UTC()),
+			LocalTime: transition.Time.In(schedule.Location()).Format(time.RFC3339),
+		})
+	}
+	return transitions
+}
+
+// reconcileSuspended applies the suspend policy of a suspended window. With the
+// Wake policy its snoozed resources are woken once, when the window is suspended,
+// so a workload that another window snoozes afterwards is left alone.
+func (r *SnoozeWindowReconciler) reconcileSuspended(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager) error {
+	logger := logf.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionSuspended,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ResourcesLeft",
+		Message:            "SnoozeWindow is suspended, resources were left as they are",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if snoozeWindow.Spec.SuspendPolicy != schedulingv1alpha1.SuspendPolicyLeave {
+		condition.Reason = "ResourcesWoken"
+		condition.Message = "SnoozeWindow is suspended, its snoozed resources were woken"
+	}
+
+	previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSuspended)
+	if previous != nil && previous.Status == metav1.ConditionTrue && previous.Reason == condition.Reason {
+		return nil
+	}
+
+	logger.Info("Suspending SnoozeWindow", "policy", snoozeWindow.Spec.SuspendPolicy)
+	if condition.Reason == "ResourcesWoken" {
+		if err := resourceManager.WakeAll(ctx, r.Client); err != nil {
+			return err
+		}
+	}
+	r.Recorder.Event(snoozeWindow, corev1.EventTypeNormal, "Suspended", condition.Message)
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+	return nil
+}
+
+// reconcileWakeOverrides records the wake overrides of the window and of its
+// resources in status and Events. It reports whether the window itself is held
+// awake, and the earliest time a resource's own override ends.
+func (r *SnoozeWindowReconciler) reconcileWakeOverrides(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) (bool, time.Time) {
+	logger := logf.FromContext(ctx)
+	status := &snoozeWindow.Status
+
+	until, windowOverride, err := utils.WakeOverrideUntil(snoozeWindow.GetAnnotations(), now)
+	if err != nil {
+		logger.Error(err, "parsing wake override", "annotation", schedulingv1alpha1.WakeUntilAnnotation)
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "InvalidWakeOverride", err.Error())
+	}
+
+	switch {
+	case windowOverride:
+		if status.WakeOverrideUntil == nil || !status.WakeOverrideUntil.Equal(&metav1.Time{Time: until}) {
+			r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "WakeOverride",
+				"Resources are held awake until %s", until.Format(time.RFC3339))
+		}
+		status.WakeOverrideUntil = &metav1.Time{Time: until}
+	case status.WakeOverrideUntil != nil:
+		r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "WakeOverrideExpired",
+			"Wake override ended at %s, resuming the snooze schedule", status.WakeOverrideUntil.Format(time.RFC3339))
+		status.WakeOverrideUntil = nil
+	}
+
+	var nextExpiry time.Time
+	var overridden []string
+	for _, resource := range resourceManager.WakeOverrides(now) {
+		resourceUntil, _, _ := utils.WakeOverrideUntil(resource.GetAnnotations(), now)
+		if nextExpiry.IsZero() || resourceUntil.Before(nextExpiry) {
+			nextExpiry = resourceUntil
+		}
+		overridden = append(overridden, fmt.Sprintf("%s/%s until %s",
+			resource.GetResourceType(), resource.GetName(), resourceUntil.Format(time.RFC3339)))
+
+		if resource.IsSnoozed() && !windowOverride {
+			r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "WakeOverride",
+				"Waking %s %s, held awake until %s", resource.GetResourceType(), resource.GetName(), resourceUntil.Format(time.RFC3339))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionWakeOverride,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoOverride",
+		Message:            "No wake override is in effect",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	switch {
+	case windowOverride:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "WindowOverride"
+		condition.Message = fmt.Sprintf("All resources are held awake until %s", until.Format(time.RFC3339))
+	case len(overridden) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ResourceOverride"
+		condition.Message = "Resources held awake: " + strings.Join(overridden, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	return windowOverride, nextExpiry
+}
+
+// reconcileManualSnooze records an on-demand snooze requested through
+// spec.suspendUntil or the snooze-until annotation in status and Events. It
+// returns the time the snooze ends while one is in effect, and whether one that
+// was in effect has just ended.
+func (r *SnoozeWindowReconciler) reconcileManualSnooze(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, now time.Time) (time.Time, bool) {
+	logger := logf.FromContext(ctx)
+	status := &snoozeWindow.Status
+
+	until, _, err := utils.SnoozeOverrideUntil(snoozeWindow.GetAnnotations(), now)
+	if err != nil {
+		logger.Error(err, "parsing on-demand snooze", "annotation", schedulingv1alpha1.SnoozeUntilAnnotation)
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "InvalidManualSnooze", err.Error())
+	}
+	if suspendUntil := snoozeWindow.Spec.SuspendUntil; suspendUntil != nil && suspendUntil.After(until) {
+		until = suspendUntil.Time
+	}
+	manualSnooze := now.Before(until)
+
+	manualSnoozeEnded := false
+	switch {
+	case manualSnooze:
+		if status.ManualSnoozeUntil == nil || !status.ManualSnoozeUntil.Equal(&metav1.Time{Time: until}) {
+			r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "ManualSnooze",
+				"Snoozing resources on demand until %s", until.Format(time.RFC3339))
+		}
+		status.ManualSnoozeUntil = &metav1.Time{Time: until}
+	case status.ManualSnoozeUntil != nil:
+		r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "ManualSnoozeEnded",
+			"On-demand snooze ended at %s", status.ManualSnoozeUntil.Format(time.RFC3339))
+		status.ManualSnoozeUntil = nil
+		manualSnoozeEnded = true
+	}
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionManualSnooze,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoManualSnooze",
+		Message:            "No on-demand snooze is in effect",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if manualSnooze {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ManualSnooze"
+		condition.Message = fmt.Sprintf("Resources are snoozed on demand until %s", until.Format(time.RFC3339))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if !manualSnooze {
+		return time.Time{}, manualSnoozeEnded
+	}
+	return until, manualSnoozeEnded
+}
+
+// recordOutcome reports what the reconcile did to the window's resources. A
+// dry-run window reports the changes it would have made, any other window
+// updates its savings and metrics.
+func (r *SnoozeWindowReconciler) recordOutcome(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) {
+	status := &snoozeWindow.Status
+
+	if !snoozeWindow.Spec.DryRun {
+		status.DryRunChanges = nil
+		meta.RemoveStatusCondition(&status.Conditions, schedulingv1alpha1.ConditionDryRun)
+
+		r.reconcileSavings(ctx, snoozeWindow, resourceManager, now)
+		metrics.RecordSnoozedResources(snoozeWindow.Name, snoozeWindow.Namespace, resourceManager.Owned())
+		return
+	}
+
+	// Only a new plan is announced, a repeated one is already in status
+	changes := resourceManager.DryRunChanges()
+	if !equality.Semantic.DeepEqual(changes, status.DryRunChanges) {
+		for _, change := range changes {
+			r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "DryRun"+change.Action,
+				"Would %s %s %s", strings.ToLower(change.Action), change.Kind, change.Name)
 		}
+	}
+	status.DryRunChanges = changes
 
-		// Change to Rerun at the time of the Snooze Start time
-		logger.Info("RequeingScheduler", "interval", "10 seconds")
-		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionDryRun,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NoChanges",
+		Message:            "Dry run, no resources would be changed",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if len(changes) > 0 {
+		condition.Reason = "ChangesPending"
+		condition.Message = fmt.Sprintf("Dry run, %d resource(s) would be changed", len(changes))
 	}
+	meta.SetStatusCondition(&status.Conditions, condition)
 }
 
-func (r *SnoozeWindowReconciler) buildResourceManager(ctx context.Context, namespace string, labelSelectors map[string]string) (*adapter.ResourceManager, error) {
-	resourceManager := adapter.NewResourceManager()
+// updateStatus writes the status of snoozeWindow if it changed during the reconcile.
+func (r *SnoozeWindowReconciler) updateStatus(ctx context.Context, original, snoozeWindow *schedulingv1alpha1.SnoozeWindow) error {
+	if equality.Semantic.DeepEqual(original.Status, snoozeWindow.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, snoozeWindow)
+}
 
-	// Fetching Workloads
-	var deploymentsList appsv1.DeploymentList
-	if err := r.List(ctx, &deploymentsList, client.InNamespace(namespace), client.MatchingLabels(labelSelectors)); err != nil {
-		return nil, err
+// findSnoozeWindowsForObject maps a workload event to every SnoozeWindow in the
+// workload's namespace whose label selector matches it, so changes made during a
+// window are enforced without waiting for the next requeue.
+func (r *SnoozeWindowReconciler) findSnoozeWindowsForObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := r.List(ctx, &snoozeWindows, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SnoozeWindows", "namespace", obj.GetNamespace())
+		return nil
 	}
-	for _, deploy := range deploymentsList.Items {
-		resourceManager.AddResource(workloads.NewDeploymentAdapter(&deploy))
+
+	var requests []reconcile.Request
+	for _, snoozeWindow := range snoozeWindows.Items {
+		selector := labels.SelectorFromSet(snoozeWindow.Spec.LabelSelector)
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snoozeWindow.Name, Namespace: snoozeWindow.Namespace},
+		})
 	}
+	return requests
+}
 
-	var statefulsetsList appsv1.StatefulSetList
-	if err := r.List(ctx, &statefulsetsList, client.InNamespace(namespace), client.MatchingLabels(labelSelectors)); err != nil {
-		return nil, err
+// findSnoozeWindowsForCalendar maps a SnoozeCalendar event to every SnoozeWindow
+// in its namespace that references it.
+func (r *SnoozeWindowReconciler) findSnoozeWindowsForCalendar(ctx context.Context, obj client.Object) []reconcile.Request {
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := r.List(ctx, &snoozeWindows, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SnoozeWindows", "namespace", obj.GetNamespace())
+		return nil
 	}
-	for _, statefulset := range statefulsetsList.Items {
-		resourceManager.AddResource(workloads.NewStatefulSetAdapter(&statefulset))
+
+	var requests []reconcile.Request
+	for _, snoozeWindow := range snoozeWindows.Items {
+		if !slices.ContainsFunc(snoozeWindow.Spec.Calendars, func(reference schedulingv1alpha1.CalendarReference) bool {
+			return reference.Name == obj.GetName()
+		}) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snoozeWindow.Name, Namespace: snoozeWindow.Namespace},
+		})
 	}
+	return requests
+}
 
-	// Fetching Jobs
-	var jobsList batchv1.JobList
-	if err := r.List(ctx, &jobsList, client.InNamespace(namespace), client.MatchingLabels(labelSelectors)); err != nil {
-		return nil, err
+// findSnoozeWindowsForConfigMap maps a ConfigMap event to every SnoozeWindow
+// referencing a SnoozeCalendar that imports its iCalendar file, so edits to the
+// file reach the windows' schedules.
+func (r *SnoozeWindowReconciler) findSnoozeWindowsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	var calendars schedulingv1alpha1.SnoozeCalendarList
+	if err := r.List(ctx, &calendars, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SnoozeCalendars", "namespace", obj.GetNamespace())
+		return nil
 	}
-	for _, job := range jobsList.Items {
-		resourceManager.AddResource(jobs.NewJobAdapter(&job))
+
+	var requests []reconcile.Request
+	for _, calendar := range calendars.Items {
+		if calendar.Spec.ICalendar == nil || calendar.Spec.ICalendar.ConfigMapName != obj.GetName() {
+			continue
+		}
+		for _, request := range r.findSnoozeWindowsForCalendar(ctx, &calendar) {
+			if !slices.Contains(requests, request) {
+				requests = append(requests, request)
+			}
+		}
 	}
+	return requests
+}
 
-	var cronjobsList batchv1.CronJobList
-	if err := r.List(ctx, &cronjobsList, client.InNamespace(namespace), client.MatchingLabels(labelSelectors)); err != nil {
-		return nil, err
+// findSnoozeWindowsForActivator maps an EndpointSlice of an activator Service
+// to every SnoozeWindow routing its Services through that activator, so they
+// are pointed at its new endpoints.
+func (r *SnoozeWindowReconciler) findSnoozeWindowsForActivator(ctx context.Context, obj client.Object) []reconcile.Request {
+	serviceName, exists := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !exists {
+		return nil
 	}
-	for _, cronjob := range cronjobsList.Items {
-		resourceManager.AddResource(jobs.NewCronJobAdapter(&cronjob))
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := r.List(ctx, &snoozeWindows); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SnoozeWindows")
+		return nil
 	}
 
-	return resourceManager, nil
+	activator := obj.GetNamespace() + "/" + serviceName
+	var requests []reconcile.Request
+	for _, snoozeWindow := range snoozeWindows.Items {
+		if snoozeWindow.Spec.Activator == nil || snoozeWindow.Spec.Activator.Service != activator {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snoozeWindow.Name, Namespace: snoozeWindow.Namespace},
+		})
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SnoozeWindowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Status churn on workloads is ignored, only spec, label and annotation changes matter
+	workloadPredicates := builder.WithPredicates(predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	))
+	mapToSnoozeWindows := handler.EnqueueRequestsFromMapFunc(r.findSnoozeWindowsForObject)
+
+	// Status writes are skipped so the savings bookkeeping does not trigger a reconcile of its own
+	windowPredicates := builder.WithPredicates(predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	))
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&schedulingv1alpha1.SnoozeWindow{}).
+		For(&schedulingv1alpha1.SnoozeWindow{}, windowPredicates).
+		Watches(&appsv1.Deployment{}, mapToSnoozeWindows, workloadPredicates).
+		Watches(&appsv1.StatefulSet{}, mapToSnoozeWindows, workloadPredicates).
+		Watches(&batchv1.Job{}, mapToSnoozeWindows, workloadPredicates).
+		Watches(&batchv1.CronJob{}, mapToSnoozeWindows, workloadPredicates).
+		Watches(&corev1.Service{}, mapToSnoozeWindows, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.findSnoozeWindowsForActivator)).
+		Watches(&schedulingv1alpha1.SnoozeWindow{}, handler.EnqueueRequestsFromMapFunc(r.findPeerSnoozeWindows), windowPredicates).
+		Watches(&schedulingv1alpha1.SnoozeCalendar{}, handler.EnqueueRequestsFromMapFunc(r.findSnoozeWindowsForCalendar)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.findSnoozeWindowsForConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Named("snoozewindow").
 		Complete(r)
 }
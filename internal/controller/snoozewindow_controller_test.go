@@ -18,16 +18,28 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/utils"
 )
 
 var _ = Describe("SnoozeWindow Controller", func() {
@@ -69,8 +81,9 @@ var _ = Describe("SnoozeWindow Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &SnoozeWindowReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -81,4 +94,357 @@ var _ = Describe("SnoozeWindow Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When a window is overridden, snoozed on demand or suspended", func() {
+		// run returns a daily schedule from start to end, in UTC.
+		run := func(start, end time.Time) schedulingv1alpha1.SnoozeScheduleSpec {
+			return schedulingv1alpha1.SnoozeScheduleSpec{
+				StartTime: start.UTC().Format(utils.TimeLayout),
+				EndTime:   end.UTC().Format(utils.TimeLayout),
+				Days:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"},
+			}
+		}
+
+		var (
+			now          time.Time
+			deployment   *appsv1.Deployment
+			snoozeWindow *schedulingv1alpha1.SnoozeWindow
+			reconciler   *SnoozeWindowReconciler
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			deployment = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: map[string]string{"app": "overrides"}},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](2),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "overrides"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "overrides"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "nginx"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			// The window is created by each spec, once its schedule and overrides are set
+			snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+				ObjectMeta: metav1.ObjectMeta{Name: "overrides", Namespace: "default", Annotations: map[string]string{}},
+				Spec: schedulingv1alpha1.SnoozeWindowSpec{
+					Timezone:      "UTC",
+					LabelSelector: map[string]string{"app": "overrides"},
+					ResourceTypes: []string{schedulingv1alpha1.ResourceTypeDeployment},
+				},
+			}
+			reconciler = &SnoozeWindowReconciler{
+				Client:    k8sClient,
+				Scheme:    k8sClient.Scheme(),
+				Recorder:  record.NewFakeRecorder(100),
+				APIReader: k8sClient,
+			}
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, snoozeWindow))).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, deployment))).To(Succeed())
+		})
+
+		reconcileWindow := func() (reconcile.Result, *schedulingv1alpha1.SnoozeWindow, *appsv1.Deployment) {
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(snoozeWindow)})
+			Expect(err).NotTo(HaveOccurred())
+
+			stored := &schedulingv1alpha1.SnoozeWindow{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snoozeWindow), stored)).To(Succeed())
+			storedDeployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), storedDeployment)).To(Succeed())
+			return result, stored, storedDeployment
+		}
+
+		It("Should snooze a workload scaled up during the run again", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+			_, _, storedDeployment := reconcileWindow()
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+
+			By("scaling the snoozed deployment up behind the window's back")
+			Expect(reconciler.findSnoozeWindowsForObject(ctx, storedDeployment)).To(ConsistOf(reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(snoozeWindow),
+			}))
+			patch := client.MergeFrom(storedDeployment.DeepCopy())
+			storedDeployment.Spec.Replicas = ptr.To[int32](3)
+			Expect(k8sClient.Patch(ctx, storedDeployment, patch)).To(Succeed())
+
+			result, _, storedDeployment := reconcileWindow()
+
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			// The replica count from before the run is kept to wake it with
+			Expect(storedDeployment.Annotations).To(HaveKeyWithValue(workloads.BackupReplicasKey, "2"))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 2*time.Hour))
+		})
+
+		It("Should hold the resources awake through a scheduled run and come back when the override ends", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			snoozeWindow.Annotations[schedulingv1alpha1.WakeUntilAnnotation] = now.Add(30 * time.Minute).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			condition := meta.FindStatusCondition(stored.Status.Conditions, schedulingv1alpha1.ConditionWakeOverride)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("WindowOverride"))
+			Expect(stored.Status.WakeOverrideUntil).NotTo(BeNil())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+			// The override ends well before the run, the window snoozes from then on
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Minute))
+		})
+
+		It("Should snooze the run once the override has ended", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			snoozeWindow.Annotations[schedulingv1alpha1.WakeUntilAnnotation] = now.Add(-time.Minute).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, schedulingv1alpha1.ConditionWakeOverride)).To(BeTrue())
+			Expect(stored.Status.WakeOverrideUntil).To(BeNil())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			Expect(storedDeployment.Annotations).To(HaveKeyWithValue(workloads.BackupReplicasKey, "2"))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 2*time.Hour))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 3*time.Hour))
+		})
+
+		It("Should keep a resource with its own override awake and come back when it ends", func() {
+			patch := client.MergeFrom(deployment.DeepCopy())
+			deployment.Annotations = map[string]string{
+				schedulingv1alpha1.WakeUntilAnnotation: now.Add(20 * time.Minute).UTC().Format(time.RFC3339),
+			}
+			Expect(k8sClient.Patch(ctx, deployment, patch)).To(Succeed())
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			condition := meta.FindStatusCondition(stored.Status.Conditions, schedulingv1alpha1.ConditionWakeOverride)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ResourceOverride"))
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 20*time.Minute))
+		})
+
+		It("Should snooze on demand outside the schedule and come back when the snooze ends", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(2*time.Hour), now.Add(3*time.Hour))
+			snoozeWindow.Annotations[schedulingv1alpha1.SnoozeUntilAnnotation] = now.Add(30 * time.Minute).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			condition := meta.FindStatusCondition(stored.Status.Conditions, schedulingv1alpha1.ConditionManualSnooze)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ManualSnooze"))
+			Expect(stored.Status.ManualSnoozeUntil).NotTo(BeNil())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 25*time.Minute))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Minute))
+		})
+
+		It("Should stay snoozed until an on-demand snooze outlasting the run ends", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(time.Hour))
+			snoozeWindow.Annotations[schedulingv1alpha1.SnoozeUntilAnnotation] = now.Add(3 * time.Hour).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, schedulingv1alpha1.ConditionManualSnooze)).To(BeTrue())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 2*time.Hour))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 3*time.Hour))
+		})
+
+		It("Should let a wake override win over an on-demand snooze", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(2*time.Hour), now.Add(3*time.Hour))
+			snoozeWindow.Annotations[schedulingv1alpha1.SnoozeUntilAnnotation] = now.Add(time.Hour).UTC().Format(time.RFC3339)
+			snoozeWindow.Annotations[schedulingv1alpha1.WakeUntilAnnotation] = now.Add(30 * time.Minute).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, schedulingv1alpha1.ConditionManualSnooze)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, schedulingv1alpha1.ConditionWakeOverride)).To(BeTrue())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Minute))
+		})
+
+		It("Should wake the resources once suspended and not requeue", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+			_, _, storedDeployment := reconcileWindow()
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+
+			stored := &schedulingv1alpha1.SnoozeWindow{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snoozeWindow), stored)).To(Succeed())
+			stored.Spec.Suspended = true
+			Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			condition := meta.FindStatusCondition(stored.Status.Conditions, schedulingv1alpha1.ConditionSuspended)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ResourcesWoken"))
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+
+		It("Should leave the resources snoozed when suspended with the Leave policy", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+			reconcileWindow()
+
+			stored := &schedulingv1alpha1.SnoozeWindow{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snoozeWindow), stored)).To(Succeed())
+			stored.Spec.Suspended = true
+			stored.Spec.SuspendPolicy = schedulingv1alpha1.SuspendPolicyLeave
+			Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			condition := meta.FindStatusCondition(stored.Status.Conditions, schedulingv1alpha1.ConditionSuspended)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("ResourcesLeft"))
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+
+		It("Should follow the schedule again once resumed", func() {
+			snoozeWindow.Spec.SnoozeSchedule = run(now.Add(-time.Hour), now.Add(3*time.Hour))
+			snoozeWindow.Spec.Suspended = true
+			Expect(k8sClient.Create(ctx, snoozeWindow)).To(Succeed())
+			_, _, storedDeployment := reconcileWindow()
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+
+			stored := &schedulingv1alpha1.SnoozeWindow{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snoozeWindow), stored)).To(Succeed())
+			stored.Spec.Suspended = false
+			Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+
+			result, stored, storedDeployment := reconcileWindow()
+
+			Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, schedulingv1alpha1.ConditionSuspended)).To(BeTrue())
+			Expect(storedDeployment.Spec.Replicas).To(HaveValue(BeZero()))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 2*time.Hour))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 3*time.Hour))
+		})
+	})
+})
+
+var _ = Describe("Workload watches", func() {
+	var (
+		ctx        context.Context
+		reconciler *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		window := func(namespace, name string, selector map[string]string) *schedulingv1alpha1.SnoozeWindow {
+			return &schedulingv1alpha1.SnoozeWindow{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       schedulingv1alpha1.SnoozeWindowSpec{LabelSelector: selector},
+			}
+		}
+		reconciler = &SnoozeWindowReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				window("default", "api", map[string]string{"app": "api"}),
+				window("default", "api-nightly", map[string]string{"app": "api", "tier": "backend"}),
+				window("default", "web", map[string]string{"app": "web"}),
+				window("default", "everything", nil),
+				window("staging", "api", map[string]string{"app": "api"}),
+			).Build(),
+		}
+	})
+
+	It("Should map a workload to the windows in its namespace whose selector matches it", func() {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "default", Labels: map[string]string{"app": "api", "tier": "backend"},
+		}}
+
+		Expect(reconciler.findSnoozeWindowsForObject(ctx, deployment)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "api", Namespace: "default"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "api-nightly", Namespace: "default"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "everything", Namespace: "default"}},
+		))
+	})
+
+	It("Should map a workload no selector matches only to the windows selecting everything", func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: "report", Namespace: "default", Labels: map[string]string{"app": "report"},
+		}}
+
+		Expect(reconciler.findSnoozeWindowsForObject(ctx, job)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "everything", Namespace: "default"}},
+		))
+	})
+
+	It("Should map nothing in a namespace without windows", func() {
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "postgres", Namespace: "production", Labels: map[string]string{"app": "api"},
+		}}
+
+		Expect(reconciler.findSnoozeWindowsForObject(ctx, statefulSet)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Calendar watches", func() {
+	var (
+		ctx        context.Context
+		reconciler *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		calendar := func(name, configMap string) *schedulingv1alpha1.SnoozeCalendar {
+			calendar := &schedulingv1alpha1.SnoozeCalendar{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			if configMap != "" {
+				calendar.Spec.ICalendar = &schedulingv1alpha1.ICalendarSource{ConfigMapName: configMap}
+			}
+			return calendar
+		}
+		window := func(name string, calendars ...string) *schedulingv1alpha1.SnoozeWindow {
+			snoozeWindow := &schedulingv1alpha1.SnoozeWindow{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			for _, calendar := range calendars {
+				snoozeWindow.Spec.Calendars = append(snoozeWindow.Spec.Calendars, schedulingv1alpha1.CalendarReference{Name: calendar})
+			}
+			return snoozeWindow
+		}
+		reconciler = &SnoozeWindowReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				calendar("holidays", "public-holidays"),
+				calendar("holidays-2027", "public-holidays"),
+				calendar("releases", ""),
+				window("nightly", "holidays", "holidays-2027"),
+				window("weekend", "holidays"),
+				window("batch", "releases"),
+			).Build(),
+		}
+	})
+
+	It("Should map a ConfigMap to the windows referencing a calendar importing it", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "public-holidays", Namespace: "default"}}
+
+		Expect(reconciler.findSnoozeWindowsForConfigMap(ctx, configMap)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "nightly", Namespace: "default"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "weekend", Namespace: "default"}},
+		))
+	})
+
+	It("Should map a ConfigMap no calendar imports to nothing", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "public-holidays", Namespace: "staging"}}
+
+		Expect(reconciler.findSnoozeWindowsForConfigMap(ctx, configMap)).To(BeEmpty())
+	})
 })
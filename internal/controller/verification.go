@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+// defaultWakeTimeout is how long woken workloads have to become available
+// when the window does not set spec.wakeTimeout.
+const defaultWakeTimeout = 10 * time.Minute
+
+// startWakeVerification records the start of a wake, after which the woken
+// workloads are checked on until they are available.
+func (r *SnoozeWindowReconciler) startWakeVerification(snoozeWindow *schedulingv1alpha1.SnoozeWindow, now time.Time) {
+	snoozeWindow.Status.LastWakeTime = &metav1.Time{Time: now}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionWakeVerified,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Verifying",
+		Message:            "Waiting for the woken workloads to become available",
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+}
+
+// verifyWake checks on the workloads woken by the last wake of the window. It
+// sets the WakeVerified condition once all of them are available, and the
+// WakeDegraded condition when pods crash-loop or the wake timeout passes first.
+// It returns true while the workloads are still being checked on.
+func (r *SnoozeWindowReconciler) verifyWake(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, now time.Time) (bool, error) {
+	logger := logf.FromContext(ctx)
+
+	verified := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeVerified)
+	if verified == nil || verified.Status != metav1.ConditionUnknown || snoozeWindow.Status.LastWakeTime == nil {
+		return false, nil
+	}
+	elapsed := now.Sub(snoozeWindow.Status.LastWakeTime.Time)
+
+	var unavailable, crashLooping []string
+	for _, resource := range resourceManager.Resources() {
+		replicated, ok := resource.(snoozetypes.ReplicatedResource)
+		if !ok || resource.IsSnoozed() {
+			continue
+		}
+		available, desired := replicated.Availability()
+		if available >= desired {
+			recordAvailable(snoozeWindow, resource, now)
+			continue
+		}
+		unavailable = append(unavailable, fmt.Sprintf("%s/%s (%d/%d available)",
+			resource.GetResourceType(), resource.GetName(), available, desired))
+
+		pods, err := r.crashLoopingPods(ctx, replicated)
+		if err != nil {
+			return false, err
+		}
+		crashLooping = append(crashLooping, pods...)
+	}
+
+	if len(unavailable) == 0 {
+		message := fmt.Sprintf("The woken workloads became available within %s", elapsed.Round(time.Second))
+		logger.Info("Woken workloads are available", "elapsed", elapsed)
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeNormal, "WakeVerified", message)
+		r.setWakeConditions(snoozeWindow, metav1.ConditionTrue, "Available", message, metav1.ConditionFalse, "Available", message)
+		return false, nil
+	}
+
+	timeout := wakeTimeout(snoozeWindow)
+	timedOut := elapsed >= timeout
+	previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeDegraded)
+
+	switch {
+	case len(crashLooping) > 0:
+		message := "Pods are crash-looping after the wake: " + strings.Join(crashLooping, ", ")
+		if previous == nil || previous.Status != metav1.ConditionTrue || previous.Reason != "CrashLoopBackOff" {
+			r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "CrashLoopBackOff", message)
+		}
+		verifiedStatus, verifiedReason := metav1.ConditionUnknown, "Verifying"
+		if timedOut {
+			verifiedStatus, verifiedReason = metav1.ConditionFalse, "Timeout"
+		}
+		r.setWakeConditions(snoozeWindow, verifiedStatus, verifiedReason, message, metav1.ConditionTrue, "CrashLoopBackOff", message)
+	case timedOut:
+		message := fmt.Sprintf("Workloads not available within %s of the wake: %s", timeout, strings.Join(unavailable, ", "))
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "WakeDegraded", message)
+		r.setWakeConditions(snoozeWindow, metav1.ConditionFalse, "Timeout", message, metav1.ConditionTrue, "Timeout", message)
+	default:
+		logger.Info("Waiting for woken workloads to become available", "unavailable", unavailable)
+		return true, nil
+	}
+
+	if timedOut {
+		logger.Info("Woken workloads did not become available", "timeout", timeout, "unavailable", unavailable)
+	}
+	return !timedOut, nil
+}
+
+// wakeTimeout returns how long the workloads woken by the window have to
+// become available.
+func wakeTimeout(snoozeWindow *schedulingv1alpha1.SnoozeWindow) time.Duration {
+	if snoozeWindow.Spec.WakeTimeout != nil {
+		return snoozeWindow.Spec.WakeTimeout.Duration
+	}
+	return defaultWakeTimeout
+}
+
+// reportOverdueTiers reports the tiers a wake moved past because they were not
+// ready within the wake timeout.
+func (r *SnoozeWindowReconciler) reportOverdueTiers(snoozeWindow *schedulingv1alpha1.SnoozeWindow, tiers []string) {
+	if len(tiers) == 0 {
+		return
+	}
+	message := fmt.Sprintf("Tiers not ready within %s of the wake, woke the next tiers anyway: %s",
+		wakeTimeout(snoozeWindow), strings.Join(tiers, ", "))
+	if previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeDegraded); previous == nil ||
+		previous.Status != metav1.ConditionTrue || previous.Message != message {
+		r.Recorder.Event(snoozeWindow, corev1.EventTypeWarning, "WakeDegraded", message)
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionWakeDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "TierTimeout",
+		Message:            message,
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+}
+
+func (r *SnoozeWindowReconciler) setWakeConditions(snoozeWindow *schedulingv1alpha1.SnoozeWindow,
+	verifiedStatus metav1.ConditionStatus, verifiedReason, verifiedMessage string,
+	degradedStatus metav1.ConditionStatus, degradedReason, degradedMessage string) {
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionWakeVerified,
+		Status:             verifiedStatus,
+		Reason:             verifiedReason,
+		Message:            verifiedMessage,
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionWakeDegraded,
+		Status:             degradedStatus,
+		Reason:             degradedReason,
+		Message:            degradedMessage,
+		ObservedGeneration: snoozeWindow.Generation,
+	})
+}
+
+// crashLoopingPods returns the pod/container names of the resource's pods
+// whose containers are in CrashLoopBackOff.
+func (r *SnoozeWindowReconciler) crashLoopingPods(ctx context.Context, resource snoozetypes.ReplicatedResource) ([]string, error) {
+	if resource.PodSelector() == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(resource.PodSelector())
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(resource.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	var crashLooping []string
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+				crashLooping = append(crashLooping, pod.Name+"/"+status.Name)
+			}
+		}
+	}
+	return crashLooping, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Wake verification", func() {
+	var (
+		ctx          context.Context
+		now          time.Time
+		deployment   *appsv1.Deployment
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+		recorder     *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		selector := map[string]string{"app": "api"}
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](2),
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+			},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+		}
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{WakeTimeout: &metav1.Duration{Duration: 5 * time.Minute}},
+		}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	verify := func(objects ...*corev1.Pod) bool {
+		builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+		for _, pod := range objects {
+			builder = builder.WithObjects(pod)
+		}
+		reconciler := &SnoozeWindowReconciler{Client: builder.Build(), Recorder: recorder}
+		resourceManager := adapter.NewResourceManager()
+		resourceManager.AddResource(workloads.NewDeploymentAdapter(deployment))
+
+		verifying, err := reconciler.verifyWake(ctx, snoozeWindow, resourceManager, now)
+		Expect(err).NotTo(HaveOccurred())
+		return verifying
+	}
+
+	It("Should report the wake verified once the workloads are available", func() {
+		(&SnoozeWindowReconciler{}).startWakeVerification(snoozeWindow, now.Add(-time.Minute))
+		deployment.Status.AvailableReplicas = 2
+
+		Expect(verify()).To(BeFalse())
+		Expect(meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeVerified)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeDegraded)).To(BeTrue())
+	})
+
+	It("Should report crash-looping pods and keep checking until the timeout", func() {
+		(&SnoozeWindowReconciler{}).startWakeVerification(snoozeWindow, now.Add(-time.Minute))
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: map[string]string{"app": "api"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "server",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		}
+
+		Expect(verify(pod)).To(BeTrue())
+		degraded := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Reason).To(Equal("CrashLoopBackOff"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("api-1/server")))
+	})
+
+	It("Should report the wake degraded once the timeout has passed", func() {
+		(&SnoozeWindowReconciler{}).startWakeVerification(snoozeWindow, now.Add(-10*time.Minute))
+
+		Expect(verify()).To(BeFalse())
+		Expect(meta.IsStatusConditionFalse(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeVerified)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionWakeDegraded)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("deployment/api (1/2 available)")))
+	})
+})
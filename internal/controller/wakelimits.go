@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+)
+
+// WakeLimits paces the wakes of the windows a reconciler handles, so that
+// windows ending at the same time do not start every workload at once. Each
+// limit spaces wakes evenly, and a resource is only woken once every limit
+// that applies to it has a free slot.
+type WakeLimits struct {
+	// ResourcesPerMinute limits the wakes across the operator. Zero means no limit.
+	ResourcesPerMinute int
+	// NamespaceResourcesPerMinute limits the wakes of each namespace. Zero
+	// means no limit.
+	NamespaceResourcesPerMinute int
+	// Jitter delays each scheduled wake by up to this long, by an offset fixed
+	// for each window, unless the window sets its own.
+	Jitter time.Duration
+
+	mu sync.Mutex
+	// next holds the earliest time of the next wake for the operator (""),
+	// each namespace and each window with a limit of its own.
+	next map[string]time.Time
+}
+
+// wakePacer is the WakeLimiter of a single window, which takes a slot from
+// every limit that applies to it.
+type wakePacer struct {
+	limits    *WakeLimits
+	keys      []string
+	intervals []time.Duration
+}
+
+// limiterFor returns the WakeLimiter of snoozeWindow, or nil when no limit
+// applies to it. A nil WakeLimits applies none.
+func (l *WakeLimits) limiterFor(snoozeWindow *schedulingv1alpha1.SnoozeWindow) adapter.WakeLimiter {
+	if l == nil {
+		return nil
+	}
+
+	pacer := &wakePacer{limits: l}
+	add := func(key string, perMinute int) {
+		if perMinute > 0 {
+			pacer.keys = append(pacer.keys, key)
+			pacer.intervals = append(pacer.intervals, time.Minute/time.Duration(perMinute))
+		}
+	}
+	add("", l.ResourcesPerMinute)
+	add("namespace/"+snoozeWindow.Namespace, l.NamespaceResourcesPerMinute)
+	if rate := snoozeWindow.Spec.WakeRate; rate != nil {
+		add("window/"+snoozeWindow.Namespace+"/"+snoozeWindow.Name, int(rate.ResourcesPerMinute))
+	}
+	if len(pacer.keys) == 0 {
+		return nil
+	}
+	return pacer
+}
+
+func (p *wakePacer) Reserve(now time.Time) time.Duration {
+	p.limits.mu.Lock()
+	defer p.limits.mu.Unlock()
+	if p.limits.next == nil {
+		p.limits.next = make(map[string]time.Time)
+	}
+
+	var delay time.Duration
+	for _, key := range p.keys {
+		if wait := p.limits.next[key].Sub(now); wait > delay {
+			delay = wait
+		}
+	}
+	if delay > 0 {
+		return delay
+	}
+	for i, key := range p.keys {
+		p.limits.next[key] = now.Add(p.intervals[i])
+	}
+	return 0
+}
+
+// jitterFor returns how long after the end of a run of its schedule
+// snoozeWindow starts waking. The offset is derived from the window's name so
+// it stays the same across reconciles and restarts.
+func (l *WakeLimits) jitterFor(snoozeWindow *schedulingv1alpha1.SnoozeWindow) time.Duration {
+	var jitter time.Duration
+	if l != nil {
+		jitter = l.Jitter
+	}
+	if rate := snoozeWindow.Spec.WakeRate; rate != nil && rate.Jitter != nil {
+		jitter = rate.Jitter.Duration
+	}
+	if jitter <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(snoozeWindow.Namespace + "/" + snoozeWindow.Name))
+	return time.Duration(hash.Sum64() % uint64(jitter))
+}
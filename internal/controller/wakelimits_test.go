@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+var _ = Describe("Wake limits", func() {
+	window := func(namespace, name string, rate *schedulingv1alpha1.WakeRateSpec) *schedulingv1alpha1.SnoozeWindow {
+		return &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{WakeRate: rate},
+		}
+	}
+
+	It("Should space wakes across windows by the tightest limit that applies", func() {
+		now := time.Now()
+		limits := &WakeLimits{ResourcesPerMinute: 6, NamespaceResourcesPerMinute: 2}
+		dev := limits.limiterFor(window("dev", "nightly", nil))
+		devPaced := limits.limiterFor(window("dev", "paced", &schedulingv1alpha1.WakeRateSpec{ResourcesPerMinute: 1}))
+		staging := limits.limiterFor(window("staging", "nightly", nil))
+
+		Expect(dev.Reserve(now)).To(BeZero())
+		Expect(staging.Reserve(now)).To(Equal(10 * time.Second))
+		Expect(staging.Reserve(now.Add(10 * time.Second))).To(BeZero())
+		Expect(dev.Reserve(now.Add(20 * time.Second))).To(Equal(10 * time.Second))
+		Expect(devPaced.Reserve(now.Add(30 * time.Second))).To(BeZero())
+		Expect(devPaced.Reserve(now.Add(60 * time.Second))).To(Equal(30 * time.Second))
+	})
+
+	It("Should not limit windows without any limit", func() {
+		Expect((&WakeLimits{}).limiterFor(window("dev", "nightly", nil))).To(BeNil())
+		Expect((*WakeLimits)(nil).limiterFor(window("dev", "nightly", nil))).To(BeNil())
+	})
+
+	It("Should delay each window by a fixed offset within the jitter", func() {
+		limits := &WakeLimits{Jitter: 10 * time.Minute}
+		nightly := window("dev", "nightly", nil)
+		offset := limits.jitterFor(nightly)
+
+		Expect(offset).To(BeNumerically(">=", 0))
+		Expect(offset).To(BeNumerically("<", 10*time.Minute))
+		Expect(limits.jitterFor(nightly)).To(Equal(offset))
+		Expect(limits.jitterFor(window("dev", "nightly", &schedulingv1alpha1.WakeRateSpec{
+			Jitter: &metav1.Duration{},
+		}))).To(BeZero())
+	})
+})
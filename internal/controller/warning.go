@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/notify"
+	snoozetypes "codeacme.org/kube-snooze/internal/pkg/types"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// snoozeWarningAt returns when the warning period before a snooze starting at
+// snoozeAt begins, or false when the window does not warn.
+func snoozeWarningAt(snoozeWindow *schedulingv1alpha1.SnoozeWindow, snoozeAt time.Time) (time.Time, bool) {
+	if snoozeWindow.Spec.WarningPeriod == nil || snoozeWindow.Spec.WarningPeriod.Duration <= 0 {
+		return time.Time{}, false
+	}
+	return snoozeAt.Add(-snoozeWindow.Spec.WarningPeriod.Duration), true
+}
+
+// reconcileSnoozeWarning warns about the snooze starting at snoozeAt. The
+// awake resources that are not held awake past snoozeAt by a wake override get
+// the snoozing-at annotation, and a new warning is announced with an Event and
+// a notification. A zero snoozeAt ends the warning, removing the annotation
+// from every resource of the window. A dry-run window leaves its resources and
+// the webhook alone.
+func (r *SnoozeWindowReconciler) reconcileSnoozeWarning(ctx context.Context, snoozeWindow *schedulingv1alpha1.SnoozeWindow, resourceManager *adapter.ResourceManager, snoozeAt time.Time) error {
+	logger := logf.FromContext(ctx)
+
+	var warned []snoozetypes.SnoozableResource
+	for _, resource := range resourceManager.Resources() {
+		value := ""
+		if !snoozeAt.IsZero() && !resource.IsSnoozed() {
+			if _, heldAwake, _ := utils.WakeOverrideUntil(resource.GetAnnotations(), snoozeAt); !heldAwake {
+				value = snoozeAt.UTC().Format(time.RFC3339)
+				warned = append(warned, resource)
+			}
+		}
+
+		if resource.GetAnnotations()[schedulingv1alpha1.SnoozingAtAnnotation] == value || snoozeWindow.Spec.DryRun {
+			continue
+		}
+		if err := resource.Annotate(ctx, r.Client, schedulingv1alpha1.SnoozingAtAnnotation, value); err != nil {
+			logger.Error(err, "Failed to annotate resource with the upcoming snooze",
+				"type", resource.GetResourceType(),
+				"name", resource.GetName())
+			return err
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               schedulingv1alpha1.ConditionSnoozeWarning,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoSnoozePending",
+		Message:            "No snooze is about to start",
+		ObservedGeneration: snoozeWindow.Generation,
+	}
+	if len(warned) == 0 {
+		meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+		return nil
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "SnoozePending"
+	condition.Message = fmt.Sprintf("Snoozing resources at %s, set the %s annotation to keep them awake",
+		snoozeAt.UTC().Format(time.RFC3339), schedulingv1alpha1.WakeUntilAnnotation)
+
+	// A warning is announced once, overrides set in reply to it do not repeat it
+	previous := meta.FindStatusCondition(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSnoozeWarning)
+	if previous == nil || previous.Message != condition.Message {
+		names := resourceNames(warned)
+		logger.Info("Warning of upcoming snooze", "at", snoozeAt, "resources", names)
+		r.Recorder.Eventf(snoozeWindow, corev1.EventTypeNormal, "SnoozeWarning", "%s: %s",
+			condition.Message, strings.Join(names, ", "))
+		if snoozeWindow.Spec.Notifications != nil && !snoozeWindow.Spec.DryRun {
+			notifier := &windowNotifier{r: r, snoozeWindow: snoozeWindow}
+			notifier.send(ctx, notify.Message{
+				Window:    snoozeWindow.Name,
+				Namespace: snoozeWindow.Namespace,
+				Event:     string(schedulingv1alpha1.NotificationEventSnoozing),
+				Action:    schedulingv1alpha1.ActionSnooze,
+				Resources: names,
+				At:        snoozeAt,
+			})
+		}
+	}
+	meta.SetStatusCondition(&snoozeWindow.Status.Conditions, condition)
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+)
+
+var _ = Describe("Snooze warning", func() {
+	var (
+		ctx          context.Context
+		snoozeAt     time.Time
+		api, worker  *appsv1.Deployment
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+		recorder     *record.FakeRecorder
+		reconciler   *SnoozeWindowReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		snoozeAt = time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+		api = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		}
+		worker = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default", Annotations: map[string]string{
+				schedulingv1alpha1.WakeUntilAnnotation: "2026-10-19T23:00:00Z",
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+		}
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       schedulingv1alpha1.SnoozeWindowSpec{WarningPeriod: &metav1.Duration{Duration: 15 * time.Minute}},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SnoozeWindowReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(api, worker).Build(),
+			Recorder: recorder,
+		}
+	})
+
+	warn := func(at time.Time) {
+		resourceManager := adapter.NewResourceManager()
+		for _, deployment := range []*appsv1.Deployment{api, worker} {
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+			resourceManager.AddResource(workloads.NewDeploymentAdapter(deployment))
+		}
+		Expect(reconciler.reconcileSnoozeWarning(ctx, snoozeWindow, resourceManager, at)).To(Succeed())
+	}
+
+	It("Should start warning once the warning period begins", func() {
+		warnAt, warns := snoozeWarningAt(snoozeWindow, snoozeAt)
+		Expect(warns).To(BeTrue())
+		Expect(warnAt).To(Equal(time.Date(2026, 10, 19, 19, 45, 0, 0, time.UTC)))
+
+		snoozeWindow.Spec.WarningPeriod = nil
+		_, warns = snoozeWarningAt(snoozeWindow, snoozeAt)
+		Expect(warns).To(BeFalse())
+	})
+
+	It("Should annotate the resources about to be snoozed and announce the warning once", func() {
+		warn(snoozeAt)
+		Expect(api.Annotations).To(HaveKeyWithValue(schedulingv1alpha1.SnoozingAtAnnotation, "2026-10-19T20:00:00Z"))
+		Expect(worker.Annotations).NotTo(HaveKey(schedulingv1alpha1.SnoozingAtAnnotation))
+		Expect(meta.IsStatusConditionTrue(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSnoozeWarning)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("SnoozeWarning")))
+
+		warn(snoozeAt)
+		Expect(recorder.Events).NotTo(Receive())
+
+		warn(time.Time{})
+		Expect(api.Annotations).NotTo(HaveKey(schedulingv1alpha1.SnoozingAtAnnotation))
+		Expect(meta.IsStatusConditionFalse(snoozeWindow.Status.Conditions, schedulingv1alpha1.ConditionSnoozeWarning)).To(BeTrue())
+	})
+})
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+// Operations recorded by the operation metrics.
+const (
+	OperationSnooze = "snooze"
+	OperationWake   = "wake"
+)
+
+// resourceTypes are the kinds reported by the per-window gauges, so a kind
+// without snoozed resources reads 0 instead of keeping its last value.
+var resourceTypes = []string{
+	schedulingv1alpha1.ResourceTypeDeployment,
+	schedulingv1alpha1.ResourceTypeStatefulSet,
+	schedulingv1alpha1.ResourceTypeJob,
+	schedulingv1alpha1.ResourceTypeCronJob,
+}
+
+var (
+	SnoozedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_snooze_snoozed_resources",
+		Help: "Number of resources currently snoozed by a SnoozeWindow.",
+	}, []string{"window", "namespace", "kind"})
+
+	ReplicasSaved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_snooze_replicas_saved",
+		Help: "Number of replicas a SnoozeWindow keeps scaled down.",
+	}, []string{"window", "namespace", "kind"})
+
+	Operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_snooze_operations_total",
+		Help: "Number of snooze and wake operations on a single resource.",
+	}, []string{"operation", "kind", "namespace"})
+
+	OperationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_snooze_operation_failures_total",
+		Help: "Number of snooze and wake operations on a single resource that failed.",
+	}, []string{"operation", "kind", "namespace"})
+
+	CPUCoreHoursSaved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_snooze_cpu_core_hours_saved",
+		Help: "Estimated CPU core hours a SnoozeWindow saved by keeping replicas snoozed.",
+	}, []string{"window", "namespace"})
+
+	MemoryGiBHoursSaved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_snooze_memory_gib_hours_saved",
+		Help: "Estimated memory GiB hours a SnoozeWindow saved by keeping replicas snoozed.",
+	}, []string{"window", "namespace"})
+
+	EstimatedCostSaved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_snooze_estimated_cost_saved",
+		Help: "Estimated cost a SnoozeWindow saved, priced with the pricing ConfigMap.",
+	}, []string{"window", "namespace"})
+
+	TransitionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kube_snooze_transition_duration_seconds",
+		Help:    "Time taken to snooze or wake a single resource.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "kind"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		SnoozedResources,
+		ReplicasSaved,
+		Operations,
+		OperationFailures,
+		CPUCoreHoursSaved,
+		MemoryGiBHoursSaved,
+		EstimatedCostSaved,
+		TransitionDuration,
+	)
+}
+
+// ObserveOperation records the outcome and latency of an operation on a single
+// resource that started at start.
+func ObserveOperation(operation string, resource types.SnoozableResource, start time.Time, err error) {
+	kind, namespace := resource.GetResourceType(), resource.GetNamespace()
+
+	Operations.WithLabelValues(operation, kind, namespace).Inc()
+	if err != nil {
+		OperationFailures.WithLabelValues(operation, kind, namespace).Inc()
+	}
+	TransitionDuration.WithLabelValues(operation, kind).Observe(time.Since(start).Seconds())
+}
+
+// RecordSnoozedResources sets the gauges of a window from the state of the
+// resources it manages. Workloads scaled back up by an override are running and
+// are not counted.
+func RecordSnoozedResources(window, namespace string, resources []types.SnoozableResource) {
+	snoozed := make(map[string]int)
+	replicas := make(map[string]int)
+	for _, resource := range resources {
+		annotations := resource.GetAnnotations()
+		if !resource.IsSnoozed() {
+			continue
+		}
+		if _, overridden := annotations[workloads.OverrideKey]; overridden {
+			continue
+		}
+
+		kind := resource.GetResourceType()
+		snoozed[kind]++
+		if backup, err := strconv.Atoi(annotations[workloads.BackupReplicasKey]); err == nil {
+			replicas[kind] += backup
+		}
+	}
+
+	for _, kind := range resourceTypes {
+		SnoozedResources.WithLabelValues(window, namespace, kind).Set(float64(snoozed[kind]))
+		ReplicasSaved.WithLabelValues(window, namespace, kind).Set(float64(replicas[kind]))
+	}
+}
+
+// ForgetWindow removes the gauges of a window that no longer exists.
+func ForgetWindow(window, namespace string) {
+	labels := prometheus.Labels{"window": window, "namespace": namespace}
+	SnoozedResources.DeletePartialMatch(labels)
+	ReplicasSaved.DeletePartialMatch(labels)
+	CPUCoreHoursSaved.DeletePartialMatch(labels)
+	MemoryGiBHoursSaved.DeletePartialMatch(labels)
+	EstimatedCostSaved.DeletePartialMatch(labels)
+}
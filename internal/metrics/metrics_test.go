@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"codeacme.org/kube-snooze/internal/controller/adapter/jobs"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/pkg/types"
+)
+
+var _ = Describe("RecordSnoozedResources", func() {
+	newDeployment := func(name string, annotations map[string]string) types.SnoozableResource {
+		return workloads.NewDeploymentAdapter(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		})
+	}
+
+	AfterEach(func() {
+		ForgetWindow("nightly", "default")
+	})
+
+	It("Should count snoozed resources and the replicas they saved per kind", func() {
+		RecordSnoozedResources("nightly", "default", []types.SnoozableResource{
+			newDeployment("api", map[string]string{workloads.BackupReplicasKey: "3"}),
+			newDeployment("worker", map[string]string{workloads.BackupReplicasKey: "2"}),
+			newDeployment("overridden", map[string]string{workloads.BackupReplicasKey: "4", workloads.OverrideKey: "true"}),
+			newDeployment("awake", nil),
+			jobs.NewJobAdapter(&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+				Spec:       batchv1.JobSpec{Suspend: ptr.To(true)},
+			}),
+		})
+
+		Expect(testutil.ToFloat64(SnoozedResources.WithLabelValues("nightly", "default", "deployment"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(ReplicasSaved.WithLabelValues("nightly", "default", "deployment"))).To(Equal(5.0))
+		Expect(testutil.ToFloat64(SnoozedResources.WithLabelValues("nightly", "default", "job"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(SnoozedResources.WithLabelValues("nightly", "default", "statefulset"))).To(Equal(0.0))
+	})
+
+	It("Should drop the gauges of a forgotten window", func() {
+		RecordSnoozedResources("nightly", "default", nil)
+		Expect(testutil.CollectAndCount(SnoozedResources)).To(Equal(4))
+
+		ForgetWindow("nightly", "default")
+		Expect(testutil.CollectAndCount(SnoozedResources)).To(BeZero())
+	})
+})
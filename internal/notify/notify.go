@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultTemplate is the text of a message when the window does not set a
+// template of its own.
+const DefaultTemplate = `{{if .Error}}SnoozeWindow {{.Namespace}}/{{.Window}} failed to {{.Action}}: {{.Error}}` +
+	`{{else if eq .Event "Snoozing"}}SnoozeWindow {{.Namespace}}/{{.Window}} snoozes {{len .Resources}} resource(s) ` +
+	`at {{.At.Format "2006-01-02T15:04:05Z07:00"}}: {{join .Resources ", "}}. ` +
+	`Set the kube-snooze/wake-until annotation to keep them awake.` +
+	`{{else}}SnoozeWindow {{.Namespace}}/{{.Window}} {{lower .Event}} {{len .Resources}} resource(s): {{join .Resources ", "}}{{end}}`
+
+// defaultBackoff is the wait before the first retry of a message, doubled for
+// each further retry.
+const defaultBackoff = time.Second
+
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Message describes a transition of a SnoozeWindow to its template.
+type Message struct {
+	Window    string
+	Namespace string
+	// Event is Snoozing, Snoozed, Woken or Failed.
+	Event string
+	// Action is snooze or wake.
+	Action string
+	// Resources are the type/name of the resources the transition changed.
+	Resources []string
+	Error     string
+	// At is when a Snoozing window snoozes its resources.
+	At time.Time
+}
+
+// Parse parses text as a message template, or DefaultTemplate when text is
+// empty.
+func Parse(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	return template.New("notification").Funcs(funcs).Parse(text)
+}
+
+// Render renders message with the template text.
+func Render(text string, message Message) (string, error) {
+	tmpl, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, message); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// CheckURL reports an error unless rawURL is an http or https URL whose host is
+// among allowedHosts. An allowed host starting with "*." matches the hosts of
+// that domain.
+func CheckURL(rawURL string, allowedHosts []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("webhook URL scheme %q is not http or https", parsed.Scheme)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if !slices.ContainsFunc(allowedHosts, func(allowed string) bool {
+		allowed = strings.ToLower(allowed)
+		if domain, wildcard := strings.CutPrefix(allowed, "*."); wildcard {
+			return strings.HasSuffix(host, "."+domain)
+		}
+		return host == allowed
+	}) {
+		return fmt.Errorf("webhook host %q is not among the allowed notification hosts", host)
+	}
+	return nil
+}
+
+// Webhook posts messages as a Slack-compatible JSON body.
+type Webhook struct {
+	URL string
+	// Token is sent as a bearer token when set.
+	Token string
+	// Retries is how many times a message is sent again when the webhook
+	// cannot be reached, rate limits it or fails with a server error.
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each further
+	// one. Defaults to a second.
+	Backoff time.Duration
+	// Client defaults to an http.Client with a 10 second timeout that does
+	// not follow redirects, which could lead away from the checked URL.
+	Client *http.Client
+}
+
+type payload struct {
+	Text string `json:"text"`
+}
+
+// Send posts text to the webhook, retrying as configured.
+func (w *Webhook) Send(ctx context.Context, text string) error {
+	body, err := json.Marshal(payload{Text: text})
+	if err != nil {
+		return err
+	}
+	client := w.Client
+	if client == nil {
+		client = &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, client, body)
+		if err == nil || !retry || attempt >= w.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (w *Webhook) post(ctx context.Context, client *http.Client, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook responded with %s", resp.Status)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	var message Message
+
+	BeforeEach(func() {
+		message = Message{
+			Window:    "nightly",
+			Namespace: "staging",
+			Event:     "Snoozed",
+			Action:    "snooze",
+			Resources: []string{"deployment/api", "statefulset/db"},
+		}
+	})
+
+	It("Should describe the transition with the default template", func() {
+		Expect(Render("", message)).To(Equal("SnoozeWindow staging/nightly snoozed 2 resource(s): deployment/api, statefulset/db"))
+
+		message.Event, message.At = "Snoozing", time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+		Expect(Render("", message)).To(HavePrefix("SnoozeWindow staging/nightly snoozes 2 resource(s) at 2026-10-19T20:00:00Z: "))
+
+		message.Event, message.Error = "Failed", "deployments.apps \"api\" is forbidden"
+		Expect(Render("", message)).To(ContainSubstring("failed to snooze: deployments.apps"))
+	})
+
+	It("Should render a custom template", func() {
+		Expect(Render(":zzz: {{.Window}} {{upper .Event}}", message)).To(Equal(":zzz: nightly SNOOZED"))
+	})
+})
+
+var _ = Describe("CheckURL", func() {
+	It("Should only allow http and https URLs on the allowed hosts", func() {
+		allowed := []string{"hooks.slack.com", "*.example.com"}
+		Expect(CheckURL("https://hooks.slack.com/services/T000/B000", allowed)).To(Succeed())
+		Expect(CheckURL("https://chat.example.com/hook", allowed)).To(Succeed())
+		Expect(CheckURL("http://HOOKS.slack.com:8080/", allowed)).To(Succeed())
+
+		Expect(CheckURL("https://example.com/hook", allowed)).To(MatchError(ContainSubstring(`"example.com"`)))
+		Expect(CheckURL("http://kubernetes.default.svc/api", allowed)).To(MatchError(ContainSubstring("not among the allowed")))
+		Expect(CheckURL("http://169.254.169.254/latest", allowed)).To(MatchError(ContainSubstring("not among the allowed")))
+		Expect(CheckURL("file:///etc/passwd", allowed)).To(MatchError(ContainSubstring("not http or https")))
+		Expect(CheckURL("https://hooks.slack.com/", nil)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Webhook", func() {
+	var (
+		ctx      context.Context
+		requests []*http.Request
+		texts    []string
+		statuses []int
+		server   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests, texts, statuses = nil, nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body payload
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			requests = append(requests, r)
+			texts = append(texts, body.Text)
+
+			status := http.StatusOK
+			if len(statuses) > 0 {
+				status, statuses = statuses[0], statuses[1:]
+			}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	It("Should post the text with the token", func() {
+		webhook := &Webhook{URL: server.URL, Token: "s3cret"}
+		Expect(webhook.Send(ctx, "Snoozed")).To(Succeed())
+
+		Expect(texts).To(Equal([]string{"Snoozed"}))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer s3cret"))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+	})
+
+	It("Should retry server errors and rate limits", func() {
+		statuses = []int{http.StatusTooManyRequests, http.StatusBadGateway}
+		webhook := &Webhook{URL: server.URL, Retries: 2, Backoff: time.Millisecond}
+		Expect(webhook.Send(ctx, "Woken")).To(Succeed())
+		Expect(texts).To(HaveLen(3))
+	})
+
+	It("Should give up after the retries, and right away on a client error", func() {
+		statuses = []int{http.StatusInternalServerError, http.StatusInternalServerError}
+		webhook := &Webhook{URL: server.URL, Retries: 1, Backoff: time.Millisecond}
+		Expect(webhook.Send(ctx, "Woken")).To(MatchError(ContainSubstring("500")))
+		Expect(texts).To(HaveLen(2))
+
+		statuses = []int{http.StatusNotFound}
+		Expect(webhook.Send(ctx, "Woken")).To(MatchError(ContainSubstring("404")))
+		Expect(texts).To(HaveLen(3))
+	})
+
+	It("Should not follow redirects away from the webhook", func() {
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusTemporaryRedirect))
+		DeferCleanup(redirect.Close)
+
+		webhook := &Webhook{URL: redirect.URL}
+		Expect(webhook.Send(ctx, "Snoozed")).To(MatchError(ContainSubstring("307")))
+		Expect(texts).To(BeEmpty())
+	})
+})
@@ -3,16 +3,43 @@ package types
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type SnoozableResource interface {
 	GetName() string
 	GetNamespace() string
+	GetLabels() map[string]string
 	GetAnnotations() map[string]string
 	SetAnnotations(annotations map[string]string)
 	IsSnoozed() bool
-	Snooze(ctx context.Context, r client.Client) error
+	IsDrifted() bool
+	// IsSettled reports whether the resource has reached the state it was last
+	// set to: no replicas left running while snoozed, all of them ready while
+	// awake.
+	IsSettled() bool
+	// SnoozedRequests returns the resources the snoozed replicas would request
+	// if they were running.
+	SnoozedRequests() corev1.ResourceList
+	// Snooze snoozes the resource and records owner, the name of the window
+	// acting on it, in the snoozed-by annotation.
+	Snooze(ctx context.Context, r client.Client, owner string) error
 	Wake(ctx context.Context, r client.Client) error
+	// Annotate sets the annotation key of the resource to value, or removes it
+	// when value is empty.
+	Annotate(ctx context.Context, r client.Client, key, value string) error
 	GetResourceType() string
 }
+
+// ReplicatedResource is implemented by the resources that run replicas, whose
+// availability is checked on after they are woken.
+type ReplicatedResource interface {
+	SnoozableResource
+	// Availability returns the available replicas and the replicas the
+	// resource should run.
+	Availability() (available, desired int32)
+	// PodSelector returns the selector of the resource's pods.
+	PodSelector() *metav1.LabelSelector
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+// CalendarDates returns the dates a SnoozeCalendar lists, inline and from its
+// iCalendar file, sorted and without duplicates. Events of the file with a time
+// of day are placed on the dates they touch in loc.
+func CalendarDates(ctx context.Context, c client.Reader, calendar *schedulingv1alpha1.SnoozeCalendar, loc *time.Location) ([]string, error) {
+	var dates []string
+	for _, date := range calendar.Spec.Dates {
+		if _, err := time.Parse(DateLayout, date.Date); err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", date.Date, err)
+		}
+		dates = append(dates, date.Date)
+	}
+
+	if source := calendar.Spec.ICalendar; source != nil {
+		key := source.Key
+		if key == "" {
+			key = schedulingv1alpha1.DefaultICalendarKey
+		}
+		var configMap corev1.ConfigMap
+		if err := c.Get(ctx, client.ObjectKey{Namespace: calendar.Namespace, Name: source.ConfigMapName}, &configMap); err != nil {
+			return nil, err
+		}
+		data, exists := configMap.Data[key]
+		if !exists {
+			return nil, fmt.Errorf("ConfigMap %s has no key %q", source.ConfigMapName, key)
+		}
+		imported, err := ParseICalendar(data, loc)
+		if err != nil {
+			return nil, fmt.Errorf("ConfigMap %s key %q: %w", source.ConfigMapName, key, err)
+		}
+		dates = append(dates, imported...)
+	}
+
+	slices.Sort(dates)
+	return slices.Compact(dates), nil
+}
+
+// ApplyCalendars adds the dates of the SnoozeCalendars a SnoozeWindow
+// references to its schedule, as holidays or skipped dates.
+func ApplyCalendars(ctx context.Context, c client.Reader, snoozeWindow *schedulingv1alpha1.SnoozeWindow, schedule *Schedule) error {
+	for _, reference := range snoozeWindow.Spec.Calendars {
+		var calendar schedulingv1alpha1.SnoozeCalendar
+		if err := c.Get(ctx, client.ObjectKey{Namespace: snoozeWindow.Namespace, Name: reference.Name}, &calendar); err != nil {
+			return fmt.Errorf("SnoozeCalendar %s: %w", reference.Name, err)
+		}
+		dates, err := CalendarDates(ctx, c, &calendar, schedule.Location())
+		if err != nil {
+			return fmt.Errorf("SnoozeCalendar %s: %w", reference.Name, err)
+		}
+
+		if reference.Mode == schedulingv1alpha1.CalendarModeSkip {
+			schedule.Skip(dates...)
+		} else {
+			schedule.AddHolidays(dates...)
+		}
+	}
+	return nil
+}
+
+// LoadSchedule returns the Schedule of a SnoozeWindow with the SnoozeCalendars
+// it references applied.
+func LoadSchedule(ctx context.Context, c client.Reader, snoozeWindow *schedulingv1alpha1.SnoozeWindow) (*Schedule, error) {
+	schedule, err := ScheduleFor(snoozeWindow)
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyCalendars(ctx, c, snoozeWindow, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
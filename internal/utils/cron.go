@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five field cron expression (minute, hour, day of
+// month, month, day of week). Ranges, steps, lists and the names of months and
+// weekdays are supported, with the semantics of robfig/cron's ParseStandard
+// and 7 accepted as Sunday.
+type CronSchedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// Like cron, a day matches either day field when both are restricted. A
+	// day field starting with * (or ?), such as */2, is not restricted.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// cronSearchDays bounds the search for the next match, enough to reach a
+// 29 February from any date.
+const cronSearchDays = 8 * 366
+
+var (
+	cronMonths   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronWeekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// ParseCron parses a five field cron expression such as "0 19 * * MON-FRI".
+func ParseCron(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expression, len(fields))
+	}
+
+	var err error
+	schedule := &CronSchedule{}
+	if schedule.minutes, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hours, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.daysOfMonth, schedule.anyDayOfMonth, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month field: %w", err)
+	}
+	if schedule.months, _, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	// 7 is accepted as Sunday alongside 0
+	if schedule.daysOfWeek, schedule.anyDayOfWeek, err = parseCronField(fields[4], 0, 7, cronWeekdays); err != nil {
+		return nil, fmt.Errorf("invalid day of week field: %w", err)
+	}
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into
+// a bit set. names, when given, are the values from min onwards. unrestricted reports
+// whether a part of the field starts with * or ?, even with a step.
+func parseCronField(field string, min, max int, names []string) (bits uint64, unrestricted bool, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		low, high := min, max
+		if rangePart == "*" || rangePart == "?" {
+			unrestricted = true
+		} else {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			if low, err = parseCronValue(lowPart, min, max, names); err != nil {
+				return 0, false, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseCronValue(highPart, min, max, names); err != nil {
+					return 0, false, err
+				}
+			} else if hasStep {
+				high = max
+			}
+			if low > high {
+				return 0, false, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, unrestricted, nil
+}
+
+func parseCronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + i, nil
+		}
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < min || parsed > max {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", value, min, max)
+	}
+	return parsed, nil
+}
+
+// Next returns the first instant after t that the expression matches, in t's
+// location. Wall clock times skipped by a daylight saving change are moved
+// forward by the change, like time.Date does. The zero time is returned when
+// nothing matches, such as for 30 February.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+	for i := 0; i < cronSearchDays; i++ {
+		date := day.AddDate(0, 0, i)
+		if !c.matchesDay(date) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if c.hours&(1<<hour) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if c.minutes&(1<<minute) == 0 {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+				if candidate.After(t) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c *CronSchedule) matchesDay(date time.Time) bool {
+	if c.months&(1<<int(date.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := c.daysOfMonth&(1<<date.Day()) != 0
+	dayOfWeek := c.daysOfWeek&(1<<int(date.Weekday())) != 0
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxEventDays bounds the dates a single iCalendar event may cover.
+const maxEventDays = 366
+
+// icalEvent is the part of a VEVENT needed to know the dates it covers.
+type icalEvent struct {
+	start, end time.Time
+	allDay     bool
+	cancelled  bool
+}
+
+// ParseICalendar returns the dates, in DateLayout, covered by the events of an
+// iCalendar (.ics) file, sorted and without duplicates. All-day events cover
+// the dates from DTSTART up to DTEND, excluded. Events with a time of day cover
+// the dates they touch in loc. Cancelled events are left out and recurrence
+// rules are not expanded.
+func ParseICalendar(data string, loc *time.Location) ([]string, error) {
+	var dates []string
+	var event *icalEvent
+	for i, line := range unfoldICalendar(data) {
+		name, params, value := parseICalendarLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icalEvent{}
+		case event == nil:
+			continue
+		case name == "END" && value == "VEVENT":
+			eventDates, err := event.dates(loc)
+			if err != nil {
+				return nil, fmt.Errorf("event ending on line %d: %w", i+1, err)
+			}
+			dates = append(dates, eventDates...)
+			event = nil
+		case name == "DTSTART", name == "DTEND":
+			parsed, allDay, err := parseICalendarTime(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if name == "DTSTART" {
+				event.start, event.allDay = parsed, allDay
+			} else {
+				event.end = parsed
+			}
+		case name == "STATUS":
+			event.cancelled = strings.EqualFold(value, "CANCELLED")
+		}
+	}
+
+	slices.Sort(dates)
+	return slices.Compact(dates), nil
+}
+
+func (e *icalEvent) dates(loc *time.Location) ([]string, error) {
+	if e.start.IsZero() {
+		return nil, fmt.Errorf("missing DTSTART")
+	}
+	if e.cancelled {
+		return nil, nil
+	}
+
+	start := e.start.In(loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	end := e.end
+	switch {
+	case e.allDay && end.IsZero():
+		end = day.AddDate(0, 0, 1)
+	case end.IsZero() || !end.After(e.start):
+		// An instant still falls on the date it starts on
+		end = e.start.Add(time.Nanosecond)
+	}
+
+	var dates []string
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if len(dates) == maxEventDays {
+			return nil, fmt.Errorf("event covers more than %d days", maxEventDays)
+		}
+		dates = append(dates, day.Format(DateLayout))
+	}
+	return dates, nil
+}
+
+// unfoldICalendar splits an iCalendar file into lines, joining the lines that
+// were folded onto continuation lines starting with a space or tab.
+func unfoldICalendar(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// parseICalendarLine splits a content line such as
+// "DTSTART;TZID=Europe/Berlin:20261224T090000" into its upper-cased name, its
+// parameters and its value.
+func parseICalendarLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		key, paramValue, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value)
+}
+
+// parseICalendarTime parses a DATE or DATE-TIME value. A floating time, with
+// neither a UTC suffix nor a TZID, is read in loc.
+func parseICalendarTime(params map[string]string, value string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		parsed, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return parsed, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		parsed, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+		}
+		return parsed, false, nil
+	}
+
+	if tzid, exists := params["TZID"]; exists {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	parsed, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return parsed, false, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseICalendar", func() {
+	It("Should list the dates covered by all-day and timed events", func() {
+		data := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Christmas\r\n" +
+			"DTSTART;VALUE=DATE:20261224\r\n" +
+			"DTEND;VALUE=DATE:20261227\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:Release\r\n" +
+			"  weekend\r\n" +
+			"DTSTART;TZID=America/New_York:20261120T2\r\n" +
+			" 30000\r\n" +
+			"DTEND:20261121T120000Z\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART;VALUE=DATE:20261225\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"STATUS:CANCELLED\r\n" +
+			"DTSTART;VALUE=DATE:20260101\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+
+		dates, err := ParseICalendar(data, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dates).To(Equal([]string{"2026-11-21", "2026-12-24", "2026-12-25", "2026-12-26"}))
+	})
+
+	It("Should reject an event without a start", func() {
+		_, err := ParseICalendar("BEGIN:VEVENT\nSUMMARY:Nothing\nEND:VEVENT\n", time.UTC)
+		Expect(err).To(HaveOccurred())
+	})
+})
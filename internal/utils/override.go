@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+// timestampLayouts are the accepted override timestamp formats. Seconds may be
+// left out, as in "2026-10-17T22:00Z".
+var timestampLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00"}
+
+// ParseTimestamp parses an RFC 3339 timestamp whose seconds may be omitted. The
+// result is truncated to whole seconds, the precision kept in status.
+func ParseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Truncate(time.Second), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q, expected RFC 3339 such as 2006-01-02T15:04:05Z", value)
+}
+
+// WakeOverrideUntil returns the end of the wake override set in annotations and
+// whether it is still in effect at now. A malformed value is returned as an error.
+func WakeOverrideUntil(annotations map[string]string, now time.Time) (time.Time, bool, error) {
+	return annotationUntil(annotations, schedulingv1alpha1.WakeUntilAnnotation, now)
+}
+
+// SnoozeOverrideUntil returns the end of the on-demand snooze set in
+// annotations and whether it is still in effect at now. A malformed value is
+// returned as an error.
+func SnoozeOverrideUntil(annotations map[string]string, now time.Time) (time.Time, bool, error) {
+	return annotationUntil(annotations, schedulingv1alpha1.SnoozeUntilAnnotation, now)
+}
+
+func annotationUntil(annotations map[string]string, key string, now time.Time) (time.Time, bool, error) {
+	value, exists := annotations[key]
+	if !exists {
+		return time.Time{}, false, nil
+	}
+
+	until, err := ParseTimestamp(value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return until, now.Before(until), nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+var _ = Describe("WakeOverrideUntil", func() {
+	now := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+
+	It("should accept timestamps without seconds", func() {
+		until, active, err := WakeOverrideUntil(map[string]string{
+			schedulingv1alpha1.WakeUntilAnnotation: "2026-10-17T22:00Z",
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(active).To(BeTrue())
+		Expect(until).To(BeTemporally("==", time.Date(2026, time.October, 17, 22, 0, 0, 0, time.UTC)))
+	})
+
+	It("should report an override that has ended as inactive", func() {
+		_, active, err := WakeOverrideUntil(map[string]string{
+			schedulingv1alpha1.WakeUntilAnnotation: "2026-10-17T18:00:00+02:00",
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(active).To(BeFalse())
+	})
+
+	It("should reject malformed timestamps", func() {
+		_, active, err := WakeOverrideUntil(map[string]string{
+			schedulingv1alpha1.WakeUntilAnnotation: "tonight",
+		}, now)
+		Expect(err).To(HaveOccurred())
+		Expect(active).To(BeFalse())
+	})
+})
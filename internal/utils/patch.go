@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the field owner recorded on every write made by kube-snooze.
+const FieldManager = "kube-snooze"
+
+// PatchWithRetry applies mutate to obj and sends the difference as a merge patch
+// guarded by the object's resourceVersion. On a conflict the object is fetched
+// again and mutate is re-applied to the fresh copy, so mutate must be safe to run
+// more than once.
+func PatchWithRetry(ctx context.Context, c client.Client, obj client.Object, mutate func() error) error {
+	refetch := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if refetch {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		refetch = true
+
+		base := obj.DeepCopyObject().(client.Object)
+		if err := mutate(); err != nil {
+			return err
+		}
+
+		patch := client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+		return c.Patch(ctx, obj, patch, client.FieldOwner(FieldManager))
+	})
+}
+
+// PatchAnnotation sets the annotation key of obj to value, or removes it when
+// value is empty.
+func PatchAnnotation(ctx context.Context, c client.Client, obj client.Object, key, value string) error {
+	return PatchWithRetry(ctx, c, obj, func() error {
+		annotations := obj.GetAnnotations()
+		if value == "" {
+			delete(annotations, key)
+		} else {
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[key] = value
+		}
+		obj.SetAnnotations(annotations)
+		return nil
+	})
+}
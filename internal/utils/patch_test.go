@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("PatchWithRetry", func() {
+	ctx := context.Background()
+
+	It("should refetch the object and retry after a conflict", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+		}
+
+		conflicts := 1
+		c := fake.NewClientBuilder().
+			WithObjects(deployment).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if conflicts > 0 {
+						conflicts--
+						return errors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, obj.GetName(), nil)
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+
+		stale := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), stale)).To(Succeed())
+
+		calls := 0
+		Expect(PatchWithRetry(ctx, c, stale, func() error {
+			calls++
+			stale.Spec.Replicas = ptr.To[int32](0)
+			return nil
+		})).To(Succeed())
+		Expect(calls).To(Equal(2))
+
+		updated := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+		Expect(*updated.Spec.Replicas).To(BeZero())
+	})
+})
@@ -0,0 +1,330 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+// scheduleSearchDays bounds the search for the next occurrence of a schedule,
+// so one whose every day is excluded does not loop forever.
+const scheduleSearchDays = 2 * 366
+
+// Occurrence is a span of time during which a schedule snoozes its resources.
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Transition is an instant a schedule snoozes or wakes its resources.
+type Transition struct {
+	// Action is schedulingv1alpha1.ActionSnooze or schedulingv1alpha1.ActionWake.
+	Action string
+	Time   time.Time
+}
+
+// Schedule evaluates the union of one or more SnoozeScheduleSpecs in a
+// timezone, with the holidays and skipped dates of the window's calendars.
+type Schedule struct {
+	loc   *time.Location
+	rules []scheduleRule
+	// skipped are dates on which no rule or holiday starts
+	skipped []string
+	// holidays are the dates snoozed all day on top of the rules, sorted
+	holidays []string
+}
+
+// scheduleRule is a single parsed SnoozeScheduleSpec.
+type scheduleRule struct {
+	start, end time.Time
+	date       time.Time
+	days       []time.Weekday
+	cron       *CronSchedule
+	duration   time.Duration
+	exclusions []string
+}
+
+// NewSchedule parses spec into a Schedule evaluated in loc.
+func NewSchedule(spec schedulingv1alpha1.SnoozeScheduleSpec, loc *time.Location) (*Schedule, error) {
+	return NewSchedules([]schedulingv1alpha1.SnoozeScheduleSpec{spec}, loc)
+}
+
+// NewSchedules parses specs into a Schedule evaluated in loc that is active
+// whenever any of them is.
+func NewSchedules(specs []schedulingv1alpha1.SnoozeScheduleSpec, loc *time.Location) (*Schedule, error) {
+	schedule := &Schedule{loc: loc}
+	for i, spec := range specs {
+		rule, err := newScheduleRule(spec)
+		if err != nil {
+			if len(specs) > 1 {
+				return nil, fmt.Errorf("schedule %d: %w", i, err)
+			}
+			return nil, err
+		}
+		schedule.rules = append(schedule.rules, rule)
+	}
+	return schedule, nil
+}
+
+func newScheduleRule(spec schedulingv1alpha1.SnoozeScheduleSpec) (scheduleRule, error) {
+	rule := scheduleRule{exclusions: spec.Exclusions}
+
+	if spec.Cron != "" {
+		cron, err := ParseCron(spec.Cron)
+		if err != nil {
+			return rule, err
+		}
+		if spec.Duration == nil || spec.Duration.Duration <= 0 {
+			return rule, fmt.Errorf("a cron schedule needs a positive duration")
+		}
+		rule.cron = cron
+		rule.duration = spec.Duration.Duration
+		return rule, nil
+	}
+
+	var err error
+	if rule.start, err = time.Parse(TimeLayout, spec.StartTime); err != nil {
+		return rule, fmt.Errorf("invalid start time format: %w", err)
+	}
+	if rule.end, err = time.Parse(TimeLayout, spec.EndTime); err != nil {
+		return rule, fmt.Errorf("invalid end time format: %w", err)
+	}
+
+	if spec.Date != "" {
+		if rule.date, err = time.Parse(DateLayout, spec.Date); err != nil {
+			return rule, fmt.Errorf("invalid date format: %w", err)
+		}
+		return rule, nil
+	}
+
+	for _, day := range spec.Days {
+		weekday, err := ParseWeekday(day)
+		if err != nil {
+			return rule, err
+		}
+		rule.days = append(rule.days, weekday)
+	}
+	return rule, nil
+}
+
+// Schedules returns the schedules of a SnoozeWindow: its snoozeSchedules when
+// set, otherwise its single snoozeSchedule. A window that only snoozes idle
+// workloads has none.
+func Schedules(spec *schedulingv1alpha1.SnoozeWindowSpec) []schedulingv1alpha1.SnoozeScheduleSpec {
+	if len(spec.SnoozeSchedules) > 0 {
+		return spec.SnoozeSchedules
+	}
+	if spec.IdleSnooze != nil && equality.Semantic.DeepEqual(spec.SnoozeSchedule, schedulingv1alpha1.SnoozeScheduleSpec{}) {
+		return nil
+	}
+	return []schedulingv1alpha1.SnoozeScheduleSpec{spec.SnoozeSchedule}
+}
+
+// ScheduleFor returns the Schedule of a SnoozeWindow, evaluated in its timezone.
+// A window without a timezone is evaluated in the local timezone.
+func ScheduleFor(snoozeWindow *schedulingv1alpha1.SnoozeWindow) (*Schedule, error) {
+	loc := time.Local
+	if snoozeWindow.Spec.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(snoozeWindow.Spec.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	return NewSchedules(Schedules(&snoozeWindow.Spec), loc)
+}
+
+// ParseWeekday parses the English name of a day of the week, ignoring case.
+func ParseWeekday(day string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), day) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q", day)
+}
+
+// Location returns the timezone the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// AddHolidays snoozes all day, from midnight to midnight in the schedule's
+// timezone, on the given dates.
+func (s *Schedule) AddHolidays(dates ...string) {
+	s.holidays = slices.Concat(s.holidays, dates)
+	slices.Sort(s.holidays)
+	s.holidays = slices.Compact(s.holidays)
+}
+
+// Skip skips the occurrences, holidays included, that start on the given dates.
+func (s *Schedule) Skip(dates ...string) {
+	s.skipped = slices.Concat(s.skipped, dates)
+}
+
+// Next returns the first occurrence that ends after t, which is either ongoing
+// at t or the next to start. It reports false when the schedule has no such
+// occurrence, such as a dated window that has passed. Of the occurrences of
+// the rules and holidays, the one starting first is returned, so an ongoing
+// occurrence is preferred over one still to start.
+func (s *Schedule) Next(t time.Time) (Occurrence, bool) {
+	var next Occurrence
+	var found bool
+	consider := func(occurrence Occurrence, ok bool) {
+		if ok && (!found || occurrence.Start.Before(next.Start)) {
+			next, found = occurrence, true
+		}
+	}
+
+	for i := range s.rules {
+		consider(s.nextOccurrence(&s.rules[i], t))
+	}
+	consider(s.nextHoliday(t))
+	return next, found
+}
+
+// nextOccurrence returns the first occurrence of a single rule that ends after t.
+func (s *Schedule) nextOccurrence(rule *scheduleRule, t time.Time) (Occurrence, bool) {
+	t = t.In(s.loc)
+
+	if rule.cron != nil {
+		// The first start after t-duration is the first occurrence still running at t
+		start := t.Add(-rule.duration)
+		for i := 0; i < scheduleSearchDays; i++ {
+			start = rule.cron.Next(start)
+			if start.IsZero() {
+				return Occurrence{}, false
+			}
+			if !s.isExcluded(rule, start) {
+				return Occurrence{Start: start, End: start.Add(rule.duration)}, true
+			}
+		}
+		return Occurrence{}, false
+	}
+
+	if !rule.date.IsZero() {
+		occurrence := s.occurrenceOn(rule, rule.date)
+		return occurrence, occurrence.End.After(t) && !s.isExcluded(rule, occurrence.Start)
+	}
+
+	// An overnight window that started the day before may still be running
+	day := time.Date(t.Year(), t.Month(), t.Day()-1, 0, 0, 0, 0, s.loc)
+	for i := 0; i < scheduleSearchDays; i++ {
+		date := day.AddDate(0, 0, i)
+		if len(rule.days) > 0 && !slices.Contains(rule.days, date.Weekday()) {
+			continue
+		}
+		occurrence := s.occurrenceOn(rule, date)
+		if occurrence.End.After(t) && !s.isExcluded(rule, occurrence.Start) {
+			return occurrence, true
+		}
+	}
+	return Occurrence{}, false
+}
+
+// Active reports whether the schedule snoozes at t and, if so, until when.
+// Occurrences that overlap or touch are merged, so the returned end is the
+// next wake.
+func (s *Schedule) Active(t time.Time) (bool, time.Time) {
+	occurrence, ok := s.Next(t)
+	if !ok || occurrence.Start.After(t) {
+		return false, time.Time{}
+	}
+	return true, s.mergedEnd(occurrence)
+}
+
+// Passed reports whether an occurrence of the schedule ended within lookback
+// before t and none is running at t.
+func (s *Schedule) Passed(t time.Time, lookback time.Duration) bool {
+	if active, _ := s.Active(t); active {
+		return false
+	}
+	occurrence, ok := s.Next(t.Add(-lookback))
+	return ok && !occurrence.End.After(t)
+}
+
+// LastWake returns the end of the last occurrence that ended within lookback
+// before t, when the schedule last woke the window.
+func (s *Schedule) LastWake(t time.Time, lookback time.Duration) (time.Time, bool) {
+	var last time.Time
+	occurrence, ok := s.Next(t.Add(-lookback))
+	for ok && !occurrence.End.After(t) {
+		last = occurrence.End
+		occurrence, ok = s.Next(occurrence.End)
+	}
+	return last, !last.IsZero()
+}
+
+// NextTransitions returns the next n snoozes and wakes after t, in order. An
+// occurrence running at t contributes only its wake.
+func (s *Schedule) NextTransitions(t time.Time, n int) []Transition {
+	var transitions []Transition
+
+	occurrence, ok := s.Next(t)
+	for ok && len(transitions) < n {
+		end := s.mergedEnd(occurrence)
+		if occurrence.Start.After(t) {
+			transitions = append(transitions, Transition{Action: schedulingv1alpha1.ActionSnooze, Time: occurrence.Start})
+		}
+		if len(transitions) < n {
+			transitions = append(transitions, Transition{Action: schedulingv1alpha1.ActionWake, Time: end})
+		}
+		occurrence, ok = s.Next(end)
+	}
+	return transitions
+}
+
+// nextHoliday returns the first holiday that ends after t.
+func (s *Schedule) nextHoliday(t time.Time) (Occurrence, bool) {
+	for _, date := range s.holidays {
+		start, err := time.ParseInLocation(DateLayout, date, s.loc)
+		if err != nil {
+			continue
+		}
+		end := start.AddDate(0, 0, 1)
+		if end.After(t) && !s.isExcluded(nil, start) {
+			return Occurrence{Start: start, End: end}, true
+		}
+	}
+	return Occurrence{}, false
+}
+
+// mergedEnd follows the occurrences that start before occurrence ends and
+// returns the end of the last one.
+func (s *Schedule) mergedEnd(occurrence Occurrence) time.Time {
+	end := occurrence.End
+	for i := 0; i < scheduleSearchDays; i++ {
+		next, ok := s.Next(end)
+		if !ok || next.Start.After(end) {
+			break
+		}
+		end = next.End
+	}
+	return end
+}
+
+// occurrenceOn returns the occurrence of a start and end time rule that starts
+// on date.
+func (s *Schedule) occurrenceOn(rule *scheduleRule, date time.Time) Occurrence {
+	start := time.Date(date.Year(), date.Month(), date.Day(), rule.start.Hour(), rule.start.Minute(), 0, 0, s.loc)
+	endDay := date.Day()
+	if rule.end.Before(rule.start) {
+		endDay++
+	}
+	end := time.Date(date.Year(), date.Month(), endDay, rule.end.Hour(), rule.end.Minute(), 0, 0, s.loc)
+	return Occurrence{Start: start, End: end}
+}
+
+// isExcluded reports whether an occurrence of rule, or a holiday when rule is
+// nil, may not start at start.
+func (s *Schedule) isExcluded(rule *scheduleRule, start time.Time) bool {
+	date := start.In(s.loc).Format(DateLayout)
+	if rule != nil && slices.Contains(rule.exclusions, date) {
+		return true
+	}
+	return slices.Contains(s.skipped, date)
+}
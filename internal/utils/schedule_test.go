@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+var _ = Describe("Schedule", func() {
+	var berlin *time.Location
+
+	BeforeEach(func() {
+		var err error
+		berlin, err = time.LoadLocation("Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	at := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+	times := func(transitions []Transition) []string {
+		var formatted []string
+		for _, transition := range transitions {
+			formatted = append(formatted, transition.Action+" "+transition.Time.UTC().Format(time.RFC3339))
+		}
+		return formatted
+	}
+
+	It("Should follow the wall clock across a daylight saving change", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			StartTime: "22:00",
+			EndTime:   "06:00",
+			Days:      []string{"Saturday", "Sunday"},
+		}, berlin)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Berlin leaves summer time on 25 October 2026
+		Expect(times(schedule.NextTransitions(at("2026-10-21T12:00:00Z"), 4))).To(Equal([]string{
+			"Snooze 2026-10-24T20:00:00Z",
+			"Wake 2026-10-25T05:00:00Z",
+			"Snooze 2026-10-25T21:00:00Z",
+			"Wake 2026-10-26T05:00:00Z",
+		}))
+	})
+
+	It("Should report an overnight window that started the day before as active", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			StartTime: "22:00",
+			EndTime:   "06:00",
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+
+		active, until := schedule.Active(at("2026-10-20T03:00:00Z"))
+		Expect(active).To(BeTrue())
+		Expect(until).To(Equal(at("2026-10-20T06:00:00Z")))
+		Expect(times(schedule.NextTransitions(at("2026-10-20T03:00:00Z"), 2))).To(Equal([]string{
+			"Wake 2026-10-20T06:00:00Z",
+			"Snooze 2026-10-20T22:00:00Z",
+		}))
+	})
+
+	It("Should run a dated window once", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			StartTime: "09:00",
+			EndTime:   "17:00",
+			Date:      "2026-10-20",
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(schedule.NextTransitions(at("2026-10-19T00:00:00Z"), 10)).To(HaveLen(2))
+		Expect(schedule.Passed(at("2026-10-20T18:00:00Z"), 24*time.Hour)).To(BeTrue())
+		lastWake, woke := schedule.LastWake(at("2026-10-20T18:00:00Z"), 24*time.Hour)
+		Expect(woke).To(BeTrue())
+		Expect(lastWake).To(Equal(at("2026-10-20T17:00:00Z")))
+		Expect(schedule.NextTransitions(at("2026-10-20T18:00:00Z"), 10)).To(BeEmpty())
+	})
+
+	It("Should start a cron window at each match for its duration and skip exclusions", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			Cron:       "0 19 * * MON-FRI",
+			Duration:   &metav1.Duration{Duration: 12 * time.Hour},
+			Exclusions: []string{"2026-10-21"},
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(times(schedule.NextTransitions(at("2026-10-20T12:00:00Z"), 4))).To(Equal([]string{
+			"Snooze 2026-10-20T19:00:00Z",
+			"Wake 2026-10-21T07:00:00Z",
+			"Snooze 2026-10-22T19:00:00Z",
+			"Wake 2026-10-23T07:00:00Z",
+		}))
+	})
+
+	It("Should merge occurrences that overlap into one transition", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			Cron:     "0 */2 * * *",
+			Duration: &metav1.Duration{Duration: 3 * time.Hour},
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+
+		active, until := schedule.Active(at("2026-10-20T12:00:00Z"))
+		Expect(active).To(BeTrue())
+		Expect(until.After(at("2026-10-21T12:00:00Z"))).To(BeTrue())
+	})
+
+	It("Should be active during the union of several schedules", func() {
+		schedule, err := NewSchedules([]schedulingv1alpha1.SnoozeScheduleSpec{
+			{StartTime: "19:00", EndTime: "07:00", Days: []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}},
+			{Cron: "0 7 * * SAT", Duration: &metav1.Duration{Duration: 48 * time.Hour}},
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Friday night runs into the weekend, which ends on Monday morning
+		Expect(times(schedule.NextTransitions(at("2026-10-23T12:00:00Z"), 4))).To(Equal([]string{
+			"Snooze 2026-10-23T19:00:00Z",
+			"Wake 2026-10-26T07:00:00Z",
+			"Snooze 2026-10-26T19:00:00Z",
+			"Wake 2026-10-27T07:00:00Z",
+		}))
+		active, until := schedule.Active(at("2026-10-25T12:00:00Z"))
+		Expect(active).To(BeTrue())
+		Expect(until).To(Equal(at("2026-10-26T07:00:00Z")))
+	})
+
+	It("Should snooze all day on holidays and merge them with the runs around them", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			StartTime: "19:00",
+			EndTime:   "07:00",
+			Days:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+		schedule.AddHolidays("2026-12-24")
+
+		Expect(times(schedule.NextTransitions(at("2026-12-23T12:00:00Z"), 4))).To(Equal([]string{
+			"Snooze 2026-12-23T19:00:00Z",
+			"Wake 2026-12-25T07:00:00Z",
+			"Snooze 2026-12-25T19:00:00Z",
+			"Wake 2026-12-26T07:00:00Z",
+		}))
+	})
+
+	It("Should skip runs and holidays on skipped dates", func() {
+		schedule, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{
+			StartTime: "19:00",
+			EndTime:   "07:00",
+			Days:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		}, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+		schedule.AddHolidays("2026-12-24")
+		schedule.Skip("2026-12-24")
+
+		Expect(times(schedule.NextTransitions(at("2026-12-23T12:00:00Z"), 4))).To(Equal([]string{
+			"Snooze 2026-12-23T19:00:00Z",
+			"Wake 2026-12-24T07:00:00Z",
+			"Snooze 2026-12-25T19:00:00Z",
+			"Wake 2026-12-26T07:00:00Z",
+		}))
+	})
+
+	It("Should reject a cron schedule without a duration", func() {
+		_, err := NewSchedule(schedulingv1alpha1.SnoozeScheduleSpec{Cron: "0 19 * * *"}, time.UTC)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ParseCron", func() {
+	It("Should match the standard cron fields", func() {
+		cron, err := ParseCron("30 8-17/5 1,15 * SUN")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Either day field matches when both are restricted, and 2026-10-15 is a Thursday
+		Expect(cron.Next(time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC))).
+			To(Equal(time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)))
+		Expect(cron.Next(time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC))).
+			To(Equal(time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)))
+	})
+
+	// The expected matches are those of robfig/cron's ParseStandard, from
+	// Monday 2026-10-19 00:00 UTC unless stated otherwise
+	DescribeTable("matching like robfig/cron",
+		func(expression string, from, next time.Time) {
+			cron, err := ParseCron(expression)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cron.Next(from)).To(Equal(next))
+		},
+		Entry("a minute step", "*/15 * * * *",
+			time.Date(2026, 10, 19, 10, 7, 0, 0, time.UTC), time.Date(2026, 10, 19, 10, 15, 0, 0, time.UTC)),
+		Entry("a day of month step", "0 0 */2 * *",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)),
+		Entry("a day of week range", "30 9 * * MON-FRI",
+			time.Date(2026, 10, 23, 10, 0, 0, 0, time.UTC), time.Date(2026, 10, 26, 9, 30, 0, 0, time.UTC)),
+		Entry("a day of week step, which leaves the day of month to match too", "0 0 1 * */2",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("a day of week range over every day, which matches either day field", "0 0 1 * 0-6",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)),
+		Entry("both day fields restricted", "0 0 13 * FRI",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)),
+		Entry("a question mark day of month", "0 0 ? * SUN",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)),
+		Entry("29 February", "0 0 29 2 *",
+			time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)),
+	)
+
+	It("Should reject malformed expressions", func() {
+		for _, expression := range []string{"* * * *", "60 * * * *", "* * * FOO *", "5-1 * * * *", "*/0 * * * *"} {
+			_, err := ParseCron(expression)
+			Expect(err).To(HaveOccurred(), expression)
+		}
+	})
+})
@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// log is for logging in this package.
+var scaleguardlog = logf.Log.WithName("scaleguard")
+
+// ScaleGuardPath is the path the scale guard webhook is served on.
+const ScaleGuardPath = "/mutate-apps-v1-scale-guard"
+
+// ScaleGuardPolicy decides what happens to a scale-up of a workload while a
+// SnoozeWindow keeps it snoozed.
+type ScaleGuardPolicy string
+
+const (
+	// ScaleGuardReject denies the scale-up.
+	ScaleGuardReject ScaleGuardPolicy = "reject"
+	// ScaleGuardOverride admits the scale-up and marks the workload with the
+	// override annotation so the controller leaves it running until the window ends.
+	ScaleGuardOverride ScaleGuardPolicy = "override"
+)
+
+// SetupScaleGuardWebhookWithManager registers the scale guard webhook for
+// Deployments and StatefulSets in the manager.
+func SetupScaleGuardWebhookWithManager(mgr ctrl.Manager, policy ScaleGuardPolicy) error {
+	if policy != ScaleGuardReject && policy != ScaleGuardOverride {
+		return fmt.Errorf("unknown scale guard policy %q, expected %q or %q", policy, ScaleGuardReject, ScaleGuardOverride)
+	}
+
+	mgr.GetWebhookServer().Register(ScaleGuardPath, &webhook.Admission{Handler: &ScaleGuard{
+		Client:  mgr.GetClient(),
+		Decoder: admission.NewDecoder(mgr.GetScheme()),
+		Policy:  policy,
+	}})
+	return nil
+}
+
+// The scale guard has no kubebuilder:webhook marker. It would intercept every
+// Deployment and StatefulSet update in the cluster, so its configuration lives
+// in the opt-in config/scaleguard component, which also selects the workloads
+// by the workloads.SnoozedLabel and sets --scale-guard-policy.
+
+// ScaleGuard stops manual scale-ups of workloads that a SnoozeWindow keeps
+// snoozed, which the next reconcile would otherwise revert.
+type ScaleGuard struct {
+	Client  client.Client
+	Decoder admission.Decoder
+	Policy  ScaleGuardPolicy
+}
+
+var _ admission.Handler = &ScaleGuard{}
+
+// scaleRequest is the part of a workload update the guard needs to judge it.
+type scaleRequest struct {
+	object      client.Object
+	oldReplicas int32
+	newReplicas int32
+}
+
+// Handle implements admission.Handler.
+func (g *ScaleGuard) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	scale, err := g.decodeScaleRequest(ctx, req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if scale.newReplicas <= scale.oldReplicas {
+		return admission.Allowed("")
+	}
+
+	// The controller removes the backup in the same request that wakes a workload,
+	// so only workloads still carrying it are snoozed
+	annotations := scale.object.GetAnnotations()
+	if _, snoozed := annotations[workloads.BackupReplicasKey]; !snoozed {
+		return admission.Allowed("")
+	}
+	if _, overridden := annotations[workloads.OverrideKey]; overridden {
+		return admission.Allowed("")
+	}
+
+	windowName, err := g.snoozingWindow(ctx, scale.object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if windowName == "" {
+		return admission.Allowed("")
+	}
+
+	kind := req.Kind.Kind
+	if req.SubResource == "scale" {
+		kind = req.Resource.Resource
+	}
+	scaleguardlog.Info("Scale-up of snoozed workload", "kind", kind, "name", req.Name,
+		"namespace", req.Namespace, "snoozeWindow", windowName, "policy", g.Policy)
+
+	if g.Policy == ScaleGuardReject {
+		return admission.Denied(fmt.Sprintf(
+			"%s %s/%s is snoozed by the SnoozeWindow %q and cannot be scaled up until the window wakes it",
+			kind, req.Namespace, req.Name, windowName))
+	}
+	return g.override(ctx, req, scale.object)
+}
+
+func (g *ScaleGuard) decodeScaleRequest(ctx context.Context, req admission.Request) (*scaleRequest, error) {
+	if req.SubResource == "scale" {
+		newScale, oldScale := &autoscalingv1.Scale{}, &autoscalingv1.Scale{}
+		if err := g.Decoder.Decode(req, newScale); err != nil {
+			return nil, err
+		}
+		if err := g.Decoder.DecodeRaw(req.OldObject, oldScale); err != nil {
+			return nil, err
+		}
+
+		// A Scale carries no annotations, so they are read from the workload itself
+		var object client.Object
+		switch req.Resource.Resource {
+		case "deployments":
+			object = &appsv1.Deployment{}
+		case "statefulsets":
+			object = &appsv1.StatefulSet{}
+		default:
+			return nil, fmt.Errorf("unsupported resource %q", req.Resource.Resource)
+		}
+		key := types.NamespacedName{Name: req.Name, Namespace: req.Namespace}
+		if err := g.Client.Get(ctx, key, object); err != nil {
+			return nil, err
+		}
+
+		return &scaleRequest{
+			object:      object,
+			oldReplicas: oldScale.Spec.Replicas,
+			newReplicas: newScale.Spec.Replicas,
+		}, nil
+	}
+
+	switch req.Resource.Resource {
+	case "deployments":
+		newDeployment, oldDeployment := &appsv1.Deployment{}, &appsv1.Deployment{}
+		if err := g.Decoder.Decode(req, newDeployment); err != nil {
+			return nil, err
+		}
+		if err := g.Decoder.DecodeRaw(req.OldObject, oldDeployment); err != nil {
+			return nil, err
+		}
+		return &scaleRequest{
+			object:      newDeployment,
+			oldReplicas: ptr.Deref(oldDeployment.Spec.Replicas, 1),
+			newReplicas: ptr.Deref(newDeployment.Spec.Replicas, 1),
+		}, nil
+	case "statefulsets":
+		newStatefulSet, oldStatefulSet := &appsv1.StatefulSet{}, &appsv1.StatefulSet{}
+		if err := g.Decoder.Decode(req, newStatefulSet); err != nil {
+			return nil, err
+		}
+		if err := g.Decoder.DecodeRaw(req.OldObject, oldStatefulSet); err != nil {
+			return nil, err
+		}
+		return &scaleRequest{
+			object:      newStatefulSet,
+			oldReplicas: ptr.Deref(oldStatefulSet.Spec.Replicas, 1),
+			newReplicas: ptr.Deref(newStatefulSet.Spec.Replicas, 1),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource %q", req.Resource.Resource)
+	}
+}
+
+// snoozingWindow returns the name of the SnoozeWindow keeping obj snoozed, or
+// an empty string when none is. A window keeps the workloads it snoozed asleep
+// until it wakes them, so the snoozed-by annotation decides without evaluating
+// the window's schedule on every admission.
+func (g *ScaleGuard) snoozingWindow(ctx context.Context, obj client.Object) (string, error) {
+	owner, exists := obj.GetAnnotations()[schedulingv1alpha1.SnoozedByAnnotation]
+	if !exists {
+		return "", nil
+	}
+
+	var snoozeWindow schedulingv1alpha1.SnoozeWindow
+	if err := g.Client.Get(ctx, types.NamespacedName{Name: owner, Namespace: obj.GetNamespace()}, &snoozeWindow); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if snoozeWindow.Spec.Suspended || snoozeWindow.Spec.DryRun {
+		return "", nil
+	}
+	return snoozeWindow.Name, nil
+}
+
+// override admits the scale-up and records the override annotation on the
+// workload. Scale subresource requests cannot carry annotations, so the
+// workload is patched directly unless the request is a dry run.
+func (g *ScaleGuard) override(ctx context.Context, req admission.Request, obj client.Object) admission.Response {
+	if req.SubResource == "scale" {
+		if ptr.Deref(req.DryRun, false) {
+			return admission.Allowed("")
+		}
+		if err := utils.PatchAnnotation(ctx, g.Client, obj, workloads.OverrideKey, "true"); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		return admission.Allowed("scale-up recorded as a snooze override")
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[workloads.OverrideKey] = "true"
+	obj.SetAnnotations(annotations)
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/controller/adapter/workloads"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+var _ = Describe("ScaleGuard Webhook", func() {
+	var (
+		ctx          context.Context
+		snoozed      *appsv1.Deployment
+		snoozeWindow *schedulingv1alpha1.SnoozeWindow
+	)
+
+	updateRequest := func(oldObj, newObj runtime.Object) admission.Request {
+		oldRaw, err := json.Marshal(oldObj)
+		Expect(err).NotTo(HaveOccurred())
+		newRaw, err := json.Marshal(newObj)
+		Expect(err).NotTo(HaveOccurred())
+
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			Resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			Name:      snoozed.Name,
+			Namespace: snoozed.Namespace,
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+			Object:    runtime.RawExtension{Raw: newRaw},
+		}}
+	}
+
+	newGuard := func(policy ScaleGuardPolicy) *ScaleGuard {
+		return &ScaleGuard{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(snoozeWindow).Build(),
+			Decoder: admission.NewDecoder(testScheme),
+			Policy:  policy,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		snoozed = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "default",
+				Labels:    map[string]string{schedulingv1alpha1.OptInLabel: "true"},
+				Annotations: map[string]string{
+					workloads.BackupReplicasKey:            "3",
+					schedulingv1alpha1.SnoozedByAnnotation: "nightly",
+				},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		}
+
+		// The run that snoozed the workload is over, the window keeps it snoozed
+		// until it wakes it
+		start := time.Now().AddDate(0, 0, -2)
+		snoozeWindow = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				LabelSelector: map[string]string{schedulingv1alpha1.OptInLabel: "true"},
+				SnoozeSchedule: schedulingv1alpha1.SnoozeScheduleSpec{
+					StartTime: "19:00",
+					EndTime:   "07:00",
+					Date:      start.Format(utils.DateLayout),
+				},
+			},
+		}
+	})
+
+	It("Should reject a scale-up of a snoozed workload under the reject policy", func() {
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring(`"nightly"`))
+	})
+
+	It("Should mark a scale-up as an override under the override policy", func() {
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardOverride).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Patches).To(ContainElement(HaveField("Path", "/metadata/annotations/kube-snooze~1override")))
+	})
+
+	It("Should admit the controller waking the workload", func() {
+		woken := snoozed.DeepCopy()
+		woken.Spec.Replicas = ptr.To[int32](3)
+		woken.Annotations = nil
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, woken))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("Should admit a scale-up once the window that snoozed the workload is gone", func() {
+		snoozeWindow.Name = "weekend"
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("Should admit a scale-up of a workload no window claims", func() {
+		delete(snoozed.Annotations, schedulingv1alpha1.SnoozedByAnnotation)
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("Should admit a scale-up while the window is a dry run", func() {
+		snoozeWindow.Spec.DryRun = true
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("Should admit a scale-up while the window is suspended", func() {
+		snoozeWindow.Spec.Suspended = true
+		scaledUp := snoozed.DeepCopy()
+		scaledUp.Spec.Replicas = ptr.To[int32](2)
+
+		response := newGuard(ScaleGuardReject).Handle(ctx, updateRequest(snoozed, scaledUp))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("Should annotate the workload behind a scale subresource override", func() {
+		guard := newGuard(ScaleGuardOverride)
+		Expect(guard.Client.Create(ctx, snoozed)).To(Succeed())
+
+		oldRaw, err := json.Marshal(&autoscalingv1.Scale{Spec: autoscalingv1.ScaleSpec{Replicas: 0}})
+		Expect(err).NotTo(HaveOccurred())
+		newRaw, err := json.Marshal(&autoscalingv1.Scale{Spec: autoscalingv1.ScaleSpec{Replicas: 2}})
+		Expect(err).NotTo(HaveOccurred())
+		request := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   admissionv1.Update,
+			Kind:        metav1.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"},
+			Resource:    metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			SubResource: "scale",
+			Name:        snoozed.Name,
+			Namespace:   snoozed.Namespace,
+			OldObject:   runtime.RawExtension{Raw: oldRaw},
+			Object:      runtime.RawExtension{Raw: newRaw},
+		}}
+
+		response := guard.Handle(ctx, request)
+		Expect(response.Allowed).To(BeTrue())
+
+		var updated appsv1.Deployment
+		Expect(guard.Client.Get(ctx, client.ObjectKeyFromObject(snoozed), &updated)).To(Succeed())
+		Expect(updated.Annotations).To(HaveKeyWithValue(workloads.OverrideKey, "true"))
+	})
+})
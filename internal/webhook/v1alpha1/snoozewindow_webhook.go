@@ -0,0 +1,627 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+	"codeacme.org/kube-snooze/internal/notify"
+	"codeacme.org/kube-snooze/internal/utils"
+)
+
+// log is for logging in this package.
+var snoozewindowlog = logf.Log.WithName("snoozewindow-resource")
+
+var supportedResourceTypes = []string{
+	schedulingv1alpha1.ResourceTypeDeployment,
+	schedulingv1alpha1.ResourceTypeStatefulSet,
+	schedulingv1alpha1.ResourceTypeJob,
+	schedulingv1alpha1.ResourceTypeCronJob,
+	schedulingv1alpha1.ResourceTypeService,
+}
+
+// SetupSnoozeWindowWebhookWithManager registers the webhook for SnoozeWindow in the manager.
+// Windows without a timezone are defaulted to defaultTimezone, and windows using
+// the activator without naming its Service to activatorService.
+func SetupSnoozeWindowWebhookWithManager(mgr ctrl.Manager, defaultTimezone, activatorService string) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&schedulingv1alpha1.SnoozeWindow{}).
+		WithValidator(&SnoozeWindowCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&SnoozeWindowCustomDefaulter{DefaultTimezone: defaultTimezone, ActivatorService: activatorService}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-scheduling-codeacme-org-v1alpha1-snoozewindow,mutating=true,failurePolicy=fail,sideEffects=None,groups=scheduling.codeacme.org,resources=snoozewindows,verbs=create;update,versions=v1alpha1,name=msnoozewindow-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SnoozeWindowCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind SnoozeWindow when those are created or updated, so the stored object shows exactly what the
+// operator acts on.
+type SnoozeWindowCustomDefaulter struct {
+	DefaultTimezone  string
+	ActivatorService string
+}
+
+var _ webhook.CustomDefaulter = &SnoozeWindowCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind SnoozeWindow.
+func (d *SnoozeWindowCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	snoozewindow, ok := obj.(*schedulingv1alpha1.SnoozeWindow)
+	if !ok {
+		return fmt.Errorf("expected a SnoozeWindow object but got %T", obj)
+	}
+	snoozewindowlog.Info("Defaulting for SnoozeWindow", "name", snoozewindow.GetName())
+
+	spec := &snoozewindow.Spec
+	if spec.Timezone == "" {
+		spec.Timezone = d.DefaultTimezone
+	}
+	if len(spec.LabelSelector) == 0 {
+		spec.LabelSelector = map[string]string{schedulingv1alpha1.OptInLabel: "true"}
+	}
+	if spec.Activator != nil && spec.Activator.Service == "" {
+		spec.Activator.Service = d.ActivatorService
+	}
+
+	for _, schedule := range schedulesOf(spec, field.NewPath("spec")) {
+		schedule.spec.StartTime = normalizeTime(schedule.spec.StartTime)
+		schedule.spec.EndTime = normalizeTime(schedule.spec.EndTime)
+		// Days only apply to recurring windows, a dated window runs once and a cron
+		// expression picks its own days
+		if schedule.spec.Date == "" && schedule.spec.Cron == "" && len(schedule.spec.Days) == 0 {
+			schedule.spec.Days = weekdayNames()
+		}
+	}
+
+	return nil
+}
+
+// scheduleRef is a schedule of a SnoozeWindow and its path in the object.
+type scheduleRef struct {
+	spec *schedulingv1alpha1.SnoozeScheduleSpec
+	path *field.Path
+}
+
+// schedulesOf returns the schedules the operator evaluates for spec, which are
+// its snoozeSchedules when set and its snoozeSchedule otherwise. A window that
+// only snoozes idle workloads has none.
+func schedulesOf(spec *schedulingv1alpha1.SnoozeWindowSpec, specPath *field.Path) []scheduleRef {
+	if len(utils.Schedules(spec)) == 0 {
+		return nil
+	}
+	if len(spec.SnoozeSchedules) == 0 {
+		return []scheduleRef{{spec: &spec.SnoozeSchedule, path: specPath.Child("snoozeSchedule")}}
+	}
+	schedules := make([]scheduleRef, len(spec.SnoozeSchedules))
+	for i := range spec.SnoozeSchedules {
+		schedules[i] = scheduleRef{spec: &spec.SnoozeSchedules[i], path: specPath.Child("snoozeSchedules").Index(i)}
+	}
+	return schedules
+}
+
+// normalizeTime rewrites a time of day such as "6:20" to the canonical "06:20".
+// Values that do not parse are returned unchanged for the validator to report.
+func normalizeTime(value string) string {
+	parsed, err := time.Parse(utils.TimeLayout, value)
+	if err != nil {
+		return value
+	}
+	return parsed.Format(utils.TimeLayout)
+}
+
+// +kubebuilder:webhook:path=/validate-scheduling-codeacme-org-v1alpha1-snoozewindow,mutating=false,failurePolicy=fail,sideEffects=None,groups=scheduling.codeacme.org,resources=snoozewindows,verbs=create;update,versions=v1alpha1,name=vsnoozewindow-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SnoozeWindowCustomValidator struct is responsible for validating the SnoozeWindow resource
+// when it is created, updated, or deleted.
+type SnoozeWindowCustomValidator struct {
+	// Client is used to look up the other SnoozeWindows of a namespace when
+	// checking for conflicting schedules.
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &SnoozeWindowCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SnoozeWindow.
+func (v *SnoozeWindowCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	snoozewindow, ok := obj.(*schedulingv1alpha1.SnoozeWindow)
+	if !ok {
+		return nil, fmt.Errorf("expected a SnoozeWindow object but got %T", obj)
+	}
+	snoozewindowlog.Info("Validation for SnoozeWindow upon creation", "name", snoozewindow.GetName())
+
+	return v.validateSnoozeWindow(ctx, snoozewindow)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SnoozeWindow.
+func (v *SnoozeWindowCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	snoozewindow, ok := newObj.(*schedulingv1alpha1.SnoozeWindow)
+	if !ok {
+		return nil, fmt.Errorf("expected a SnoozeWindow object for the newObj but got %T", newObj)
+	}
+	snoozewindowlog.Info("Validation for SnoozeWindow upon update", "name", snoozewindow.GetName())
+
+	return v.validateSnoozeWindow(ctx, snoozewindow)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SnoozeWindow.
+func (v *SnoozeWindowCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// snoozeWindowWarnings returns the warnings for a valid SnoozeWindow whose
+// settings are likely not what was meant.
+func snoozeWindowWarnings(snoozewindow *schedulingv1alpha1.SnoozeWindow) admission.Warnings {
+	var warnings admission.Warnings
+	// An idle snooze is never requeued, so only a wake override would end it
+	if snoozewindow.Spec.IdleSnooze != nil && snoozewindow.Spec.Activator == nil {
+		warnings = append(warnings, fmt.Sprintf(
+			"spec.idleSnooze without spec.activator: idle workloads stay snoozed until the %s annotation is set on the window",
+			schedulingv1alpha1.WakeUntilAnnotation))
+	}
+	return warnings
+}
+
+func (v *SnoozeWindowCustomValidator) validateSnoozeWindow(ctx context.Context, snoozewindow *schedulingv1alpha1.SnoozeWindow) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+	warnings := snoozeWindowWarnings(snoozewindow)
+	allErrs := validateSnoozeWindowSpec(&snoozewindow.Spec, specPath)
+	allErrs = append(allErrs, validateOverrideAnnotations(snoozewindow.GetAnnotations())...)
+
+	// Conflicts can only be judged once the window itself is well formed
+	if len(allErrs) == 0 {
+		conflictErrs, conflictWarnings, err := v.validateNoConflicts(ctx, snoozewindow, specPath)
+		if err != nil {
+			return warnings, err
+		}
+		allErrs = append(allErrs, conflictErrs...)
+		warnings = append(warnings, conflictWarnings...)
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(
+		schema.GroupKind{Group: schedulingv1alpha1.GroupVersion.Group, Kind: "SnoozeWindow"},
+		snoozewindow.Name, allErrs)
+}
+
+func validateSnoozeWindowSpec(spec *schedulingv1alpha1.SnoozeWindowSpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(spec.LabelSelector) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("labelSelector"),
+			"an empty label selector would match every resource in the namespace"))
+	}
+
+	if _, err := time.LoadLocation(spec.Timezone); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("timezone"), spec.Timezone,
+			"must be an IANA timezone name such as \"Europe/Berlin\" or \"UTC\""))
+	}
+
+	if len(spec.SnoozeSchedules) > 0 && !equality.Semantic.DeepEqual(spec.SnoozeSchedule, schedulingv1alpha1.SnoozeScheduleSpec{}) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("snoozeSchedule"), "may not be combined with snoozeSchedules"))
+	}
+	for _, schedule := range schedulesOf(spec, specPath) {
+		allErrs = append(allErrs, validateSnoozeSchedule(schedule.spec, schedule.path)...)
+	}
+
+	calendars := make(map[string]bool, len(spec.Calendars))
+	for i, reference := range spec.Calendars {
+		if calendars[reference.Name] {
+			allErrs = append(allErrs, field.Duplicate(specPath.Child("calendars").Index(i).Child("name"), reference.Name))
+		}
+		calendars[reference.Name] = true
+	}
+
+	for i, resourceType := range spec.ResourceTypes {
+		if !slices.Contains(supportedResourceTypes, strings.ToLower(resourceType)) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("resourceTypes").Index(i),
+				resourceType, supportedResourceTypes))
+		}
+	}
+
+	if activator := spec.Activator; activator != nil {
+		activatorPath := specPath.Child("activator")
+		if namespace, name, found := strings.Cut(activator.Service, "/"); !found ||
+			len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1035Label(name)) > 0 {
+			allErrs = append(allErrs, field.Invalid(activatorPath.Child("service"), activator.Service,
+				"must name a Service as namespace/name"))
+		}
+		if activator.IdleTimeout != nil && activator.IdleTimeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(activatorPath.Child("idleTimeout"), activator.IdleTimeout.Duration.String(),
+				"must be a positive duration"))
+		}
+	} else if slices.ContainsFunc(spec.ResourceTypes, func(resourceType string) bool {
+		return strings.EqualFold(resourceType, schedulingv1alpha1.ResourceTypeService)
+	}) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("resourceTypes"),
+			"services are only snoozed through the activator, which needs spec.activator"))
+	}
+
+	if idleSnooze := spec.IdleSnooze; idleSnooze != nil {
+		allErrs = append(allErrs, validateIdleSnooze(idleSnooze, specPath.Child("idleSnooze"))...)
+	}
+
+	if spec.WakeTimeout != nil && spec.WakeTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("wakeTimeout"), spec.WakeTimeout.Duration.String(),
+			"must be a positive duration"))
+	}
+
+	if spec.WarningPeriod != nil && spec.WarningPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("warningPeriod"), spec.WarningPeriod.Duration.String(),
+			"must be a positive duration"))
+	}
+
+	if spec.WakeLeadTime != nil && spec.WakeLeadTime.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("wakeLeadTime"), spec.WakeLeadTime.Duration.String(),
+			"may not be negative"))
+	}
+	if spec.WakeRate != nil && spec.WakeRate.Jitter != nil && spec.WakeRate.Jitter.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("wakeRate", "jitter"), spec.WakeRate.Jitter.Duration.String(),
+			"may not be negative"))
+	}
+
+	tiers := make(map[string]bool, len(spec.Tiers))
+	for i, tier := range spec.Tiers {
+		tierPath := specPath.Child("tiers").Index(i)
+		if tier.Name == "" {
+			allErrs = append(allErrs, field.Required(tierPath.Child("name"), "tiers are named in status and Events"))
+		} else if tiers[tier.Name] {
+			allErrs = append(allErrs, field.Duplicate(tierPath.Child("name"), tier.Name))
+		}
+		tiers[tier.Name] = true
+		for j, resourceType := range tier.ResourceTypes {
+			if !slices.Contains(supportedResourceTypes, strings.ToLower(resourceType)) {
+				allErrs = append(allErrs, field.NotSupported(tierPath.Child("resourceTypes").Index(j),
+					resourceType, supportedResourceTypes))
+			}
+		}
+	}
+
+	allErrs = append(allErrs, validateHooks(spec.PreSnoozeHooks, specPath.Child("preSnoozeHooks"))...)
+	allErrs = append(allErrs, validateHooks(spec.PostWakeHooks, specPath.Child("postWakeHooks"))...)
+
+	if notifications := spec.Notifications; notifications != nil {
+		notificationsPath := specPath.Child("notifications")
+		if notifications.URL == "" && notifications.SecretRef == nil {
+			allErrs = append(allErrs, field.Required(notificationsPath.Child("url"),
+				"a webhook URL is needed, here or in the url key of secretRef"))
+		} else if notifications.URL != "" {
+			if parsed, err := url.Parse(notifications.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				allErrs = append(allErrs, field.Invalid(notificationsPath.Child("url"), notifications.URL,
+					"must be an absolute http or https URL"))
+			}
+		}
+		if _, err := notify.Parse(notifications.Template); err != nil {
+			allErrs = append(allErrs, field.Invalid(notificationsPath.Child("template"), notifications.Template, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateIdleSnooze checks the idle snooze of a window. The query is only run
+// by the Prometheus source, which cannot do without it.
+func validateIdleSnooze(idleSnooze *schedulingv1alpha1.IdleSnoozeSpec, idlePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if idleSnooze.Source == schedulingv1alpha1.IdleSourcePrometheus {
+		if idleSnooze.Query == "" {
+			allErrs = append(allErrs, field.Required(idlePath.Child("query"), "the Prometheus source needs a query"))
+		} else if _, err := template.New("query").Parse(idleSnooze.Query); err != nil {
+			allErrs = append(allErrs, field.Invalid(idlePath.Child("query"), idleSnooze.Query, err.Error()))
+		}
+	} else if idleSnooze.Query != "" {
+		allErrs = append(allErrs, field.Forbidden(idlePath.Child("query"), "only used by the Prometheus source"))
+	}
+	if idleSnooze.Threshold.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(idlePath.Child("threshold"), idleSnooze.Threshold.String(),
+			"must be positive"))
+	}
+	if idleSnooze.Lookback != nil && idleSnooze.Lookback.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(idlePath.Child("lookback"), idleSnooze.Lookback.Duration.String(),
+			"must be a positive duration"))
+	}
+	if idleSnooze.MinAwake != nil && idleSnooze.MinAwake.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(idlePath.Child("minAwake"), idleSnooze.MinAwake.Duration.String(),
+			"may not be negative"))
+	}
+	return allErrs
+}
+
+// validateHooks checks the lifecycle hooks of one transition. Hook names end
+// up in the names and labels of their Jobs, so they must be DNS labels.
+func validateHooks(hooks []schedulingv1alpha1.LifecycleHook, hooksPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]bool, len(hooks))
+	for i, hook := range hooks {
+		hookPath := hooksPath.Index(i)
+		if hook.Name == "" {
+			allErrs = append(allErrs, field.Required(hookPath.Child("name"), "hooks are named in their Jobs, status and Events"))
+		} else if names[hook.Name] {
+			allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), hook.Name))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(hook.Name) {
+				allErrs = append(allErrs, field.Invalid(hookPath.Child("name"), hook.Name, msg))
+			}
+		}
+		names[hook.Name] = true
+
+		if hook.JobTemplateRef.Name == "" {
+			allErrs = append(allErrs, field.Required(hookPath.Child("jobTemplateRef", "name"),
+				"must name the CronJob whose job template the hook runs"))
+		}
+		if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(hookPath.Child("timeout"), hook.Timeout.Duration.String(),
+				"must be a positive duration"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSnoozeSchedule(schedule *schedulingv1alpha1.SnoozeScheduleSpec, schedulePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if schedule.Cron != "" {
+		if _, err := utils.ParseCron(schedule.Cron); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("cron"), schedule.Cron, err.Error()))
+		}
+		if schedule.Duration == nil || schedule.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Required(schedulePath.Child("duration"),
+				"a cron schedule needs a positive duration"))
+		}
+		for _, other := range []struct {
+			name string
+			set  bool
+		}{
+			{"startTime", schedule.StartTime != ""},
+			{"endTime", schedule.EndTime != ""},
+			{"date", schedule.Date != ""},
+			{"days", len(schedule.Days) > 0},
+		} {
+			if other.set {
+				allErrs = append(allErrs, field.Forbidden(schedulePath.Child(other.name), "may not be combined with cron"))
+			}
+		}
+	} else {
+		if _, err := time.Parse(utils.TimeLayout, schedule.StartTime); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("startTime"), schedule.StartTime,
+				"must be a time of day in HH:MM format"))
+		}
+		if _, err := time.Parse(utils.TimeLayout, schedule.EndTime); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("endTime"), schedule.EndTime,
+				"must be a time of day in HH:MM format"))
+		}
+		if schedule.Duration != nil {
+			allErrs = append(allErrs, field.Forbidden(schedulePath.Child("duration"), "only applies to a cron schedule"))
+		}
+	}
+
+	if schedule.Date != "" {
+		if _, err := time.Parse(utils.DateLayout, schedule.Date); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("date"), schedule.Date,
+				"must be a date in YYYY-MM-DD format"))
+		}
+	}
+	for i, day := range schedule.Days {
+		if !isWeekday(day) {
+			allErrs = append(allErrs, field.NotSupported(schedulePath.Child("days").Index(i), day, weekdayNames()))
+		}
+	}
+	for i, date := range schedule.Exclusions {
+		if _, err := time.Parse(utils.DateLayout, date); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("exclusions").Index(i), date,
+				"must be a date in YYYY-MM-DD format"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateOverrideAnnotations checks that the wake and snooze overrides set on
+// a window hold timestamps the controller can parse.
+func validateOverrideAnnotations(annotations map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	annotationsPath := field.NewPath("metadata", "annotations")
+	for _, key := range []string{schedulingv1alpha1.WakeUntilAnnotation, schedulingv1alpha1.SnoozeUntilAnnotation} {
+		value, exists := annotations[key]
+		if !exists {
+			continue
+		}
+		if _, err := utils.ParseTimestamp(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(annotationsPath.Key(key), value,
+				"must be an RFC 3339 timestamp such as 2026-10-17T22:00:00Z"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateNoConflicts checks a window whose schedule overlaps another window of
+// the same namespace that can select the same resources. The window with the
+// higher spec.priority decides the shared resources, so an overlap between
+// windows of different priorities is only warned about. Between windows of
+// the same priority it is rejected, since which one holds the resources awake
+// would come down to the order they are reconciled in.
+func (v *SnoozeWindowCustomValidator) validateNoConflicts(ctx context.Context, snoozewindow *schedulingv1alpha1.SnoozeWindow, specPath *field.Path) (field.ErrorList, admission.Warnings, error) {
+	var allErrs field.ErrorList
+	var warnings admission.Warnings
+
+	// Suspended and dry-run windows change nothing, so they are checked once they do
+	if snoozewindow.Spec.Suspended || snoozewindow.Spec.DryRun {
+		return allErrs, warnings, nil
+	}
+
+	var snoozeWindows schedulingv1alpha1.SnoozeWindowList
+	if err := v.Client.List(ctx, &snoozeWindows, client.InNamespace(snoozewindow.Namespace)); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	for _, other := range snoozeWindows.Items {
+		if other.Name == snoozewindow.Name || other.Spec.Suspended || other.Spec.DryRun {
+			continue
+		}
+		if !selectorsOverlap(snoozewindow.Spec.LabelSelector, other.Spec.LabelSelector) ||
+			!resourceTypesOverlap(snoozewindow.Spec.ResourceTypes, other.Spec.ResourceTypes) {
+			continue
+		}
+
+		overlapping, err := schedulesOverlap(&snoozewindow.Spec, &other.Spec, now)
+		if err != nil {
+			// The other window is invalid and will never be acted on
+			continue
+		}
+		if overlapping < 0 {
+			continue
+		}
+		schedulePath := schedulesOf(&snoozewindow.Spec, specPath)[overlapping].path
+		if other.Spec.Priority != snoozewindow.Spec.Priority {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s overlaps with SnoozeWindow %q, which selects the same resources; the window with the higher spec.priority decides them",
+				schedulePath, other.Name))
+			continue
+		}
+		allErrs = append(allErrs, field.Forbidden(schedulePath, fmt.Sprintf(
+			"overlaps with SnoozeWindow %q, which selects the same resources during the same time with the same spec.priority %d; "+
+				"set a different spec.priority to decide which window wins", other.Name, other.Spec.Priority)))
+	}
+
+	return allErrs, warnings, nil
+}
+
+// selectorsOverlap reports whether a single set of labels can satisfy both
+// equality selectors, which is the case unless they require different values
+// for the same key.
+func selectorsOverlap(a, b map[string]string) bool {
+	for key, value := range a {
+		if other, exists := b[key]; exists && other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func resourceTypesOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(a, func(resourceType string) bool {
+		return slices.ContainsFunc(b, func(other string) bool {
+			return strings.EqualFold(resourceType, other)
+		})
+	})
+}
+
+// overlapHorizon bounds how far ahead recurring schedules are compared, which
+// covers every weekly schedule and the monthly and yearly cron expressions.
+const overlapHorizon = 366 * 24 * time.Hour
+
+// maxOverlapSteps bounds the occurrences walked when comparing two schedules,
+// for cron expressions that start every few minutes.
+const maxOverlapSteps = 10000
+
+// schedulesOverlap returns the index of the first schedule of a that overlaps
+// a schedule of b, or -1 when none does. A dated schedule is compared over its
+// single occurrence, and a recurring one over overlapHorizon from now.
+func schedulesOverlap(a, b *schedulingv1alpha1.SnoozeWindowSpec, now time.Time) (int, error) {
+	aLoc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return -1, err
+	}
+	bLoc, err := time.LoadLocation(b.Timezone)
+	if err != nil {
+		return -1, err
+	}
+	bSchedule, err := utils.NewSchedules(utils.Schedules(b), bLoc)
+	if err != nil {
+		return -1, err
+	}
+
+	for i, aSpec := range utils.Schedules(a) {
+		aSchedule, err := utils.NewSchedule(aSpec, aLoc)
+		if err != nil {
+			return -1, err
+		}
+		from, until := now, now.Add(overlapHorizon)
+		if aSpec.Date != "" {
+			// A dated schedule has a single occurrence
+			occurrence, _ := aSchedule.Next(time.Time{})
+			from, until = occurrence.Start, occurrence.End
+		}
+		if occurrencesOverlap(aSchedule, bSchedule, from, until) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// occurrencesOverlap reports whether an occurrence of a overlaps one of b
+// between from and until. The occurrences of both are walked in order, always
+// moving on from the one that ends first.
+func occurrencesOverlap(a, b *utils.Schedule, from, until time.Time) bool {
+	aOccurrence, aFound := a.Next(from)
+	bOccurrence, bFound := b.Next(from)
+	for step := 0; aFound && bFound && step < maxOverlapSteps; step++ {
+		if !aOccurrence.Start.Before(until) || !bOccurrence.Start.Before(until) {
+			return false
+		}
+		if aOccurrence.Start.Before(bOccurrence.End) && bOccurrence.Start.Before(aOccurrence.End) {
+			return true
+		}
+		if aOccurrence.End.Before(bOccurrence.End) {
+			aOccurrence, aFound = a.Next(aOccurrence.End)
+		} else {
+			bOccurrence, bFound = b.Next(bOccurrence.End)
+		}
+	}
+	return false
+}
+
+func isWeekday(day string) bool {
+	return slices.ContainsFunc(weekdayNames(), func(name string) bool {
+		return strings.EqualFold(name, day)
+	})
+}
+
+func weekdayNames() []string {
+	names := make([]string, 0, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		names = append(names, day.String())
+	}
+	return names
+}
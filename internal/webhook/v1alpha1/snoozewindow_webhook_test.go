@@ -0,0 +1,360 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "codeacme.org/kube-snooze/api/v1alpha1"
+)
+
+var _ = Describe("SnoozeWindow Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *schedulingv1alpha1.SnoozeWindow
+		validator SnoozeWindowCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &schedulingv1alpha1.SnoozeWindow{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec: schedulingv1alpha1.SnoozeWindowSpec{
+				LabelSelector: map[string]string{"kube-snooze/enabled": "true"},
+				Timezone:      "Europe/Berlin",
+				SnoozeSchedule: schedulingv1alpha1.SnoozeScheduleSpec{
+					StartTime: "19:00",
+					EndTime:   "07:00",
+					Date:      "2025-07-20",
+				},
+			},
+		}
+		validator = SnoozeWindowCustomValidator{Client: fake.NewClientBuilder().WithScheme(testScheme).Build()}
+	})
+
+	Context("When creating SnoozeWindow under Defaulting Webhook", func() {
+		var defaulter SnoozeWindowCustomDefaulter
+
+		BeforeEach(func() {
+			defaulter = SnoozeWindowCustomDefaulter{DefaultTimezone: "UTC", ActivatorService: "kube-snooze-system/kube-snooze-activator"}
+		})
+
+		It("Should apply defaults when fields are not set", func() {
+			obj.Spec.LabelSelector = nil
+			obj.Spec.Timezone = ""
+			obj.Spec.SnoozeSchedule.Date = ""
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Timezone).To(Equal("UTC"))
+			Expect(obj.Spec.LabelSelector).To(HaveKeyWithValue(schedulingv1alpha1.OptInLabel, "true"))
+			Expect(obj.Spec.SnoozeSchedule.Days).To(HaveLen(7))
+			Expect(obj.Spec.Activator).To(BeNil())
+
+			obj.Spec.Activator = &schedulingv1alpha1.ActivatorSpec{}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Activator.Service).To(Equal("kube-snooze-system/kube-snooze-activator"))
+		})
+
+		It("Should keep the values that are set and normalize times", func() {
+			obj.Spec.LabelSelector = map[string]string{"team": "payments"}
+			obj.Spec.SnoozeSchedule.StartTime = "6:20"
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Timezone).To(Equal("Europe/Berlin"))
+			Expect(obj.Spec.LabelSelector).To(Equal(map[string]string{"team": "payments"}))
+			Expect(obj.Spec.SnoozeSchedule.StartTime).To(Equal("06:20"))
+			Expect(obj.Spec.SnoozeSchedule.Days).To(BeEmpty())
+		})
+
+		It("Should default each of several schedules and leave snoozeSchedule empty", func() {
+			obj.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{}
+			obj.Spec.SnoozeSchedules = []schedulingv1alpha1.SnoozeScheduleSpec{
+				{StartTime: "7:00", EndTime: "19:00"},
+				{Cron: "0 7 * * SAT", Duration: &metav1.Duration{Duration: 48 * time.Hour}},
+			}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.SnoozeSchedule).To(Equal(schedulingv1alpha1.SnoozeScheduleSpec{}))
+			Expect(obj.Spec.SnoozeSchedules[0].StartTime).To(Equal("07:00"))
+			Expect(obj.Spec.SnoozeSchedules[0].Days).To(HaveLen(7))
+			Expect(obj.Spec.SnoozeSchedules[1].Days).To(BeEmpty())
+		})
+	})
+
+	Context("When creating or updating SnoozeWindow under Validating Webhook", func() {
+		It("Should admit a well formed window", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny invalid times, dates and timezones", func() {
+			obj.Spec.Timezone = "Mars/Olympus_Mons"
+			obj.Spec.SnoozeSchedule.StartTime = "25:00"
+			obj.Spec.SnoozeSchedule.Date = "20/07/2025"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.timezone")))
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.startTime")))
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.date")))
+		})
+
+		It("Should admit a cron schedule with a duration", func() {
+			obj.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{
+				Cron:       "0 19 * * MON-FRI",
+				Duration:   &metav1.Duration{Duration: 12 * time.Hour},
+				Exclusions: []string{"2025-12-24"},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a cron schedule mixed with times of day or without a duration", func() {
+			obj.Spec.SnoozeSchedule.Cron = "0 19 * * MON-FRI"
+			obj.Spec.SnoozeSchedule.Exclusions = []string{"24/12/2025"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.duration")))
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.startTime")))
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.exclusions[0]")))
+		})
+
+		It("Should validate each of several schedules and deny combining them with snoozeSchedule", func() {
+			obj.Spec.SnoozeSchedules = []schedulingv1alpha1.SnoozeScheduleSpec{
+				{StartTime: "19:00", EndTime: "07:00"},
+				{StartTime: "19:00", EndTime: "7pm"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule: Forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedules[1].endTime")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.snoozeSchedules[0]")))
+		})
+
+		It("Should deny an empty label selector", func() {
+			obj.Spec.LabelSelector = nil
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("spec.labelSelector")))
+		})
+
+		It("Should deny unknown days and resource types", func() {
+			obj.Spec.SnoozeSchedule.Days = []string{"Monday", "Funday"}
+			obj.Spec.ResourceTypes = []string{"Deployment", "pod"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.days[1]")))
+			Expect(err).To(MatchError(ContainSubstring("spec.resourceTypes[1]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.resourceTypes[0]")))
+		})
+
+		It("Should deny a calendar referenced twice", func() {
+			obj.Spec.Calendars = []schedulingv1alpha1.CalendarReference{
+				{Name: "holidays", Mode: schedulingv1alpha1.CalendarModeHoliday},
+				{Name: "holidays", Mode: schedulingv1alpha1.CalendarModeSkip},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("spec.calendars[1].name")))
+		})
+
+		It("Should deny unnamed or duplicate tiers and unknown tier resource types", func() {
+			obj.Spec.Tiers = []schedulingv1alpha1.SnoozeTier{
+				{Name: "databases", ResourceTypes: []string{"statefulset"}},
+				{Name: "databases", ResourceTypes: []string{"pod"}},
+				{},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.tiers[1].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.tiers[1].resourceTypes[0]")))
+			Expect(err).To(MatchError(ContainSubstring("spec.tiers[2].name")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.tiers[0]")))
+		})
+
+		It("Should deny lifecycle hooks without a name or job template, or with a duplicate name", func() {
+			template := corev1.LocalObjectReference{Name: "drain-queue"}
+			obj.Spec.PreSnoozeHooks = []schedulingv1alpha1.LifecycleHook{
+				{Name: "drain", JobTemplateRef: template},
+				{Name: "drain", JobTemplateRef: template},
+				{Name: "Flush_Cache", JobTemplateRef: template},
+			}
+			obj.Spec.PostWakeHooks = []schedulingv1alpha1.LifecycleHook{
+				{Name: "drain", Timeout: &metav1.Duration{}},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.preSnoozeHooks[1].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.preSnoozeHooks[2].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.postWakeHooks[0].jobTemplateRef.name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.postWakeHooks[0].timeout")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.preSnoozeHooks[0]")))
+		})
+
+		It("Should deny notifications without a webhook URL or with a template that does not parse", func() {
+			obj.Spec.Notifications = &schedulingv1alpha1.NotificationSpec{Template: "{{.Window"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications.url")))
+			Expect(err).To(MatchError(ContainSubstring("spec.notifications.template")))
+
+			obj.Spec.Notifications = &schedulingv1alpha1.NotificationSpec{SecretRef: &corev1.LocalObjectReference{Name: "slack"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should only admit services with a well formed activator", func() {
+			obj.Spec.ResourceTypes = []string{"Deployment", "Service"}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.resourceTypes")))
+
+			obj.Spec.Activator = &schedulingv1alpha1.ActivatorSpec{
+				Service:     "kube-snooze-activator",
+				IdleTimeout: &metav1.Duration{Duration: -time.Minute},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.activator.service")))
+			Expect(err).To(MatchError(ContainSubstring("spec.activator.idleTimeout")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.resourceTypes")))
+
+			obj.Spec.Activator = &schedulingv1alpha1.ActivatorSpec{Service: "kube-snooze-system/kube-snooze-activator"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit an idle snooze without a schedule and validate its settings", func() {
+			obj.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{}
+			obj.Spec.IdleSnooze = &schedulingv1alpha1.IdleSnoozeSpec{Threshold: resource.MustParse("50m")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.IdleSnooze = &schedulingv1alpha1.IdleSnoozeSpec{
+				Source:    schedulingv1alpha1.IdleSourcePrometheus,
+				Threshold: resource.MustParse("0"),
+				Lookback:  &metav1.Duration{},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.idleSnooze.query")))
+			Expect(err).To(MatchError(ContainSubstring("spec.idleSnooze.threshold")))
+			Expect(err).To(MatchError(ContainSubstring("spec.idleSnooze.lookback")))
+
+			obj.Spec.IdleSnooze = nil
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedule.startTime")))
+		})
+
+		It("Should warn that an idle snooze without an activator only ends with a wake override", func() {
+			obj.Spec.IdleSnooze = &schedulingv1alpha1.IdleSnoozeSpec{Threshold: resource.MustParse("50m")}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring(schedulingv1alpha1.WakeUntilAnnotation)))
+
+			warnings, err = validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+
+			obj.Spec.Activator = &schedulingv1alpha1.ActivatorSpec{Service: "kube-snooze-system/kube-snooze-activator"}
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny override annotations that are not timestamps", func() {
+			obj.Annotations = map[string]string{
+				schedulingv1alpha1.WakeUntilAnnotation:   "2026-10-17T22:00Z",
+				schedulingv1alpha1.SnoozeUntilAnnotation: "in two hours",
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(schedulingv1alpha1.SnoozeUntilAnnotation)))
+			Expect(err).NotTo(MatchError(ContainSubstring(schedulingv1alpha1.WakeUntilAnnotation)))
+		})
+
+		It("Should deny a window overlapping another window on the same resources", func() {
+			other := obj.DeepCopy()
+			other.Name = "evening"
+			other.Spec.SnoozeSchedule.StartTime = "18:00"
+			other.Spec.SnoozeSchedule.EndTime = "20:00"
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(`"evening"`)))
+
+			By("admitting the window once the selectors can no longer match the same resources")
+			obj.Spec.LabelSelector["team"] = "payments"
+			other.Spec.LabelSelector = map[string]string{"team": "search"}
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should compare recurring schedules over the days and cron expressions they run on", func() {
+			obj.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{
+				StartTime: "19:00",
+				EndTime:   "07:00",
+				Days:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+			}
+			other := obj.DeepCopy()
+			other.Name = "mornings"
+			other.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{
+				Cron:     "0 8 * * *",
+				Duration: &metav1.Duration{Duration: 4 * time.Hour},
+			}
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			By("denying a cron schedule running into the end of a weeknight")
+			other.Spec.SnoozeSchedule.Cron = "0 6 * * TUE"
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(`"mornings"`)))
+
+			By("denying a weekend schedule starting before Friday night ends")
+			other.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{
+				StartTime: "00:00",
+				EndTime:   "23:00",
+				Days:      []string{"Saturday", "Sunday"},
+			}
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(`"mornings"`)))
+		})
+
+		It("Should only warn of an overlap that spec.priority decides and name the overlapping schedule", func() {
+			other := obj.DeepCopy()
+			other.Name = "evening"
+			other.Spec.SnoozeSchedule.StartTime = "18:00"
+			other.Spec.SnoozeSchedule.EndTime = "20:00"
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(other).Build()
+			obj.Spec.SnoozeSchedule = schedulingv1alpha1.SnoozeScheduleSpec{}
+			obj.Spec.SnoozeSchedules = []schedulingv1alpha1.SnoozeScheduleSpec{
+				{StartTime: "08:00", EndTime: "12:00", Date: "2025-07-20"},
+				{StartTime: "19:00", EndTime: "07:00", Date: "2025-07-20"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.snoozeSchedules[1]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.snoozeSchedules[0]")))
+
+			obj.Spec.Priority = 10
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(And(ContainSubstring("spec.snoozeSchedules[1]"), ContainSubstring(`"evening"`))))
+		})
+
+		It("Should not report a conflict with the window being updated", func() {
+			validator.Client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(obj.DeepCopy()).Build()
+			Expect(validator.ValidateUpdate(ctx, obj, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})